@@ -1,17 +1,51 @@
 package main
 
 import (
+	"7dtd-monitor/internal/alert"
+	"7dtd-monitor/internal/api"
+	"7dtd-monitor/internal/bridge"
+	"7dtd-monitor/internal/collector"
+	"7dtd-monitor/internal/config"
+	"7dtd-monitor/internal/events"
+	"7dtd-monitor/internal/exporter"
+	"7dtd-monitor/internal/headless"
+	"7dtd-monitor/internal/metrics"
+	"7dtd-monitor/internal/notify"
+	"7dtd-monitor/internal/store"
 	"7dtd-monitor/internal/telnet"
 	"7dtd-monitor/internal/ui"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 )
 
 func main() {
+	os.Exit(run())
+}
+
+// run is the whole program. It returns the exit code instead of calling
+// os.Exit so that deferred cleanup, like flushing notifications, still runs.
+func run() int {
 	host := flag.String("host", "localhost", "Server Host/IP")
 	port := flag.String("port", "8081", "Telnet Port")
 	password := flag.String("password", "", "Telnet Password")
+	dataDir := flag.String("data-dir", defaultDataDir(), "Directory for the known players database and the actions.json, alerts.json, notify.json and bridge.json settings")
+	history := flag.Duration("history", time.Hour, "How much metrics history to keep in memory")
+	metricsAddr := flag.String("metrics-addr", "", "Serve Prometheus metrics on this address, e.g. :9110 (off when empty)")
+	apiAddr := flag.String("api-addr", "", "Serve the JSON API and live event feed on this address, e.g. 127.0.0.1:8090 (off when empty)")
+	apiToken := flag.String("api-token", os.Getenv("MONITOR_API_TOKEN"), "Bearer token for POST /api/command, GET /api/events and player IPs, defaults to $MONITOR_API_TOKEN (commands and events are off when empty)")
+	webhookURL := flag.String("webhook-url", "", "Post notifications to this Discord-compatible webhook, overrides notify.json")
+	headlessMode := flag.Bool("headless", false, "Run without the TUI and log events as JSON lines")
+	logFile := flag.String("log-file", "", "With -headless, append the log to this file instead of stdout")
 	flag.Parse()
 
 	if *password == "" {
@@ -20,10 +54,195 @@ func main() {
 	}
 
 	client := telnet.NewClient(*host, *port, *password)
-	app := ui.NewApp(client)
+	stream := events.NewStream(client)
+	stream.Start()
+
+	var logger *slog.Logger
+	if *headlessMode {
+		var err error
+		if logger, err = openLog(*logFile); err != nil {
+			fmt.Printf("Error opening log file: %v\n", err)
+			return 1
+		}
+
+		// A daemon may well start before the server, only a wrong password is fatal
+		if err := client.KeepConnecting(); errors.Is(err, telnet.ErrAuthFailed) {
+			logger.Error("authentication failed, check the -password flag", "host", *host, "port", *port)
+			return 1
+		} else if err != nil {
+			logger.Warn("server not reachable, retrying", "host", *host, "port", *port, "error", err)
+		}
+	} else if err := client.Connect(); err != nil {
+		// Connect first so failures are readable on a plain terminal
+		if errors.Is(err, telnet.ErrAuthFailed) {
+			fmt.Printf("Authentication failed for %s:%s: check the -password flag\n", *host, *port)
+		} else {
+			fmt.Printf("Error connecting to %s:%s: %v\n", *host, *port, err)
+		}
+		return 1
+	}
+	defer client.Close()
+
+	known, err := store.OpenKnownPlayers(filepath.Join(*dataDir, "known_players.json"))
+	if err != nil {
+		fmt.Printf("Error loading known players: %v\n", err)
+		return 1
+	}
+
+	ring := metrics.NewRing(int(*history / collector.StatsInterval))
+	coll := collector.New(client, known, ring)
+	coll.Start()
+
+	// Metrics and the API can share a listener when given the same address
+	muxes := map[string]*http.ServeMux{}
+	muxFor := func(addr string) *http.ServeMux {
+		if muxes[addr] == nil {
+			muxes[addr] = http.NewServeMux()
+		}
+		return muxes[addr]
+	}
+	if *metricsAddr != "" {
+		muxFor(*metricsAddr).Handle("GET /metrics", exporter.Handler(coll))
+	}
+	if *apiAddr != "" {
+		api.New(client, stream, coll, *apiToken).Register(muxFor(*apiAddr))
+	}
+	for addr, mux := range muxes {
+		if err := serve(addr, mux); err != nil {
+			fmt.Printf("Error starting HTTP listener on %s: %v\n", addr, err)
+			return 1
+		}
+	}
+
+	alertsCfg, err := config.LoadAlerts(filepath.Join(*dataDir, "alerts.json"))
+	if err != nil {
+		fmt.Printf("Error loading alert rules: %v\n", err)
+		return 1
+	}
+	var alerts *alert.Engine
+	if len(alertsCfg.Rules) > 0 {
+		if alerts, err = alert.New(alertsCfg, coll); err != nil {
+			fmt.Printf("Error in alert rules: %v\n", err)
+			return 1
+		}
+		alerts.Start()
+	}
+
+	notifyCfg, err := config.LoadNotifications(filepath.Join(*dataDir, "notify.json"))
+	if err != nil {
+		fmt.Printf("Error loading notification settings: %v\n", err)
+		return 1
+	}
+	if *webhookURL != "" {
+		notifyCfg.WebhookURL = *webhookURL
+	}
+	var notifier *notify.Notifier
+	if notifyCfg.WebhookURL != "" {
+		if notifier, err = notify.New(notifyCfg); err != nil {
+			fmt.Printf("Error in notification settings: %v\n", err)
+			return 1
+		}
+		defer notifier.Stop()
+	}
+
+	bridgeCfg, err := config.LoadBridge(filepath.Join(*dataDir, "bridge.json"))
+	if err != nil {
+		fmt.Printf("Error loading chat bridge settings: %v\n", err)
+		return 1
+	}
+	var chatBridge *bridge.Bridge
+	if bridgeCfg.Enabled() {
+		chatBridge = bridge.New(client, stream, bridge.NewHTTPTransport(bridgeCfg), bridgeCfg)
+		defer chatBridge.Stop()
+	}
+
+	if *headlessMode {
+		if notifier != nil {
+			notifier.OnError = func(err error) {
+				logger.Warn("notification failed", "error", err)
+			}
+			notifier.Start()
+			notifier.Watch(client, stream, coll)
+			if alerts != nil {
+				notifier.WatchAlerts(alerts)
+			}
+		}
+		if chatBridge != nil {
+			chatBridge.OnError = func(err error) {
+				logger.Warn("chat bridge", "error", err)
+			}
+			chatBridge.Start()
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := headless.Run(ctx, client, stream, coll, alerts, logger); err != nil {
+			logger.Error("monitor failed", "error", err)
+			return 1
+		}
+		return 0
+	}
+
+	actions, err := config.LoadActions(filepath.Join(*dataDir, "actions.json"))
+	if err != nil {
+		fmt.Printf("Error loading action presets: %v\n", err)
+		return 1
+	}
+
+	app := ui.NewApp(client, stream, coll, known, actions, ring, alerts)
+	if notifier != nil {
+		notifier.OnError = func(err error) {
+			app.ReportError("sending notification", err)
+		}
+		notifier.Start()
+		notifier.Watch(client, stream, coll)
+		if alerts != nil {
+			notifier.WatchAlerts(alerts)
+		}
+	}
+	if chatBridge != nil {
+		chatBridge.OnError = func(err error) {
+			app.ReportError("in chat bridge", err)
+		}
+		chatBridge.Start()
+	}
 
 	if err := app.Run(); err != nil {
 		fmt.Printf("Error running application: %v\n", err)
-		os.Exit(1)
+		return 1
+	}
+	return 0
+}
+
+// serve listens on addr right away, so a bad address fails before the TUI
+// takes over the terminal, and serves in the background
+func serve(addr string, handler http.Handler) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	go http.Serve(l, handler)
+	return nil
+}
+
+// openLog returns a JSON logger writing to path, or to stdout if path is empty
+func openLog(path string) (*slog.Logger, error) {
+	out := os.Stdout
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		out = f
+	}
+	return slog.New(slog.NewJSONHandler(out, nil)), nil
+}
+
+// defaultDataDir is ~/.config/7dtd-monitor or the platform equivalent
+func defaultDataDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "."
 	}
+	return filepath.Join(dir, "7dtd-monitor")
 }
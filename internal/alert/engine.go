@@ -0,0 +1,245 @@
+// Package alert evaluates declarative rules like "fps < 15 for 60s" against
+// the collector's stats and reports when they start and stop firing.
+package alert
+
+import (
+	"7dtd-monitor/internal/collector"
+	"7dtd-monitor/internal/config"
+	"7dtd-monitor/internal/pubsub"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Rules are checked this often, seconds_since_poll keeps counting between polls
+const evalInterval = time.Second
+
+// A snapshot older than this is left alone, the server not answering is
+// seconds_since_poll's job, not every other rule's
+const staleAfter = 3 * collector.StatsInterval
+
+// State says whether an Alert started or stopped
+type State int
+
+const (
+	Firing State = iota + 1
+	Resolved
+)
+
+func (s State) String() string {
+	switch s {
+	case Firing:
+		return "firing"
+	case Resolved:
+		return "resolved"
+	}
+	return "unknown"
+}
+
+// Alert is sent to subscribers when a rule starts or stops firing
+type Alert struct {
+	Rule      string
+	Severity  string
+	Condition string // e.g. "fps < 15"
+	Player    string // Only for per-player rules
+	State     State
+	Value     float64   // Last value seen
+	Since     time.Time // When the condition started to hold
+	Time      time.Time
+}
+
+// Subject is the rule name, plus the player for per-player rules
+func (a Alert) Subject() string {
+	if a.Player != "" {
+		return fmt.Sprintf("%s (%s)", a.Rule, a.Player)
+	}
+	return a.Rule
+}
+
+func (a Alert) String() string {
+	return fmt.Sprintf("%s: %s, now %s", a.Subject(), a.Condition, a.FormatValue())
+}
+
+// FormatValue prints whole numbers without decimals and others with one
+func (a Alert) FormatValue() string {
+	if a.Value == float64(int64(a.Value)) {
+		return fmt.Sprintf("%d", int64(a.Value))
+	}
+	return fmt.Sprintf("%.1f", a.Value)
+}
+
+// ruleState tracks one rule, or one player of a per-player rule
+type ruleState struct {
+	since     time.Time // Condition holds since, zero while it doesn't
+	firing    bool
+	lastFired time.Time
+	value     float64
+}
+
+type stateKey struct {
+	rule   int
+	player string
+}
+
+// Engine evaluates the rules every second against the collector's latest
+// snapshot
+type Engine struct {
+	coll    *collector.Collector
+	rules   []rule
+	started time.Time
+
+	mu     sync.Mutex
+	states map[stateKey]*ruleState
+
+	subs pubsub.Hub[Alert]
+}
+
+// New checks the rules. Nothing is evaluated until Start.
+func New(cfg config.Alerts, coll *collector.Collector) (*Engine, error) {
+	e := &Engine{
+		coll:   coll,
+		states: make(map[stateKey]*ruleState),
+	}
+	for _, r := range cfg.Rules {
+		parsed, err := parseRule(r)
+		if err != nil {
+			return nil, err
+		}
+		e.rules = append(e.rules, parsed)
+	}
+	return e, nil
+}
+
+// Start evaluates the rules in the background
+func (e *Engine) Start() {
+	e.started = time.Now()
+	go func() {
+		ticker := time.NewTicker(evalInterval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			e.evaluate(e.coll.Latest(), e.coll.LastResponse(), now)
+		}
+	}()
+}
+
+// Firing returns the alerts firing right now, oldest first
+func (e *Engine) Firing() []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var firing []Alert
+	for key, st := range e.states {
+		if st.firing {
+			firing = append(firing, e.alert(key, st, Firing, st.lastFired))
+		}
+	}
+	sort.Slice(firing, func(i, j int) bool {
+		return firing[i].Since.Before(firing[j].Since)
+	})
+	return firing
+}
+
+// evaluate checks the rules against snap. lastResponse is when the server
+// last answered, for seconds_since_poll.
+func (e *Engine) evaluate(snap collector.Snapshot, lastResponse, now time.Time) {
+	fresh := !snap.Time.IsZero() && now.Sub(snap.Time) < staleAfter
+
+	e.mu.Lock()
+	var alerts []Alert
+	for i, r := range e.rules {
+		values := map[string]float64{}
+		switch m, ok := metrics[r.metric]; {
+		case r.metric == metricSinceLastPoll:
+			last := lastResponse
+			if last.IsZero() {
+				last = e.started
+			}
+			values[""] = now.Sub(last).Seconds()
+		case !fresh:
+			continue
+		case m.players != nil:
+			values = m.players(snap)
+		case ok:
+			v, ok := m.value(snap)
+			if !ok {
+				continue
+			}
+			values[""] = v
+		}
+
+		for player, v := range values {
+			alerts = e.step(alerts, stateKey{i, player}, r.op(v, r.threshold), v, now)
+		}
+		// Players that left no longer meet the condition
+		for key, st := range e.states {
+			if _, seen := values[key.player]; key.rule == i && !seen {
+				alerts = e.step(alerts, key, false, st.value, now)
+			}
+		}
+	}
+	e.mu.Unlock()
+
+	for _, a := range alerts {
+		e.subs.Publish(a)
+	}
+}
+
+// step moves one rule state along and appends the alert it caused, if any.
+// Must be called with mu held.
+func (e *Engine) step(alerts []Alert, key stateKey, holds bool, value float64, now time.Time) []Alert {
+	r := e.rules[key.rule]
+	st := e.states[key]
+	if st == nil {
+		if !holds {
+			return alerts
+		}
+		st = &ruleState{}
+		e.states[key] = st
+	}
+	st.value = value
+
+	if !holds {
+		if st.firing {
+			st.firing = false
+			alerts = append(alerts, e.alert(key, st, Resolved, now))
+		}
+		st.since = time.Time{}
+		// Forget it once a new firing would be allowed anyway
+		if now.Sub(st.lastFired) >= r.cooldown {
+			delete(e.states, key)
+		}
+		return alerts
+	}
+
+	if st.since.IsZero() {
+		st.since = now
+	}
+	// Still in cooldown, the alert fires once it's over if the condition holds
+	if !st.firing && now.Sub(st.since) >= r.after && now.Sub(st.lastFired) >= r.cooldown {
+		st.firing = true
+		st.lastFired = now
+		alerts = append(alerts, e.alert(key, st, Firing, now))
+	}
+	return alerts
+}
+
+func (e *Engine) alert(key stateKey, st *ruleState, state State, at time.Time) Alert {
+	r := e.rules[key.rule]
+	return Alert{
+		Rule:      r.name,
+		Severity:  r.severity,
+		Condition: r.condition,
+		Player:    key.player,
+		State:     state,
+		Value:     st.value,
+		Since:     st.since,
+		Time:      at,
+	}
+}
+
+// Subscribe returns a channel receiving alerts as they fire and resolve, and
+// a function to stop the subscription. See pubsub.Hub.
+func (e *Engine) Subscribe() (<-chan Alert, func()) {
+	return e.subs.Subscribe()
+}
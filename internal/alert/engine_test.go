@@ -0,0 +1,184 @@
+package alert
+
+import (
+	"7dtd-monitor/internal/collector"
+	"7dtd-monitor/internal/config"
+	"7dtd-monitor/internal/model"
+	"testing"
+	"time"
+)
+
+var t0 = time.Date(2025, 12, 10, 10, 35, 0, 0, time.UTC)
+
+func newEngine(t *testing.T, rules ...config.AlertRule) (*Engine, <-chan Alert) {
+	t.Helper()
+	e, err := New(config.Alerts{Rules: rules}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.started = t0
+	alerts, cancel := e.Subscribe()
+	t.Cleanup(cancel)
+	return e, alerts
+}
+
+// received returns the alerts published so far
+func received(alerts <-chan Alert) []Alert {
+	var got []Alert
+	for {
+		select {
+		case a := <-alerts:
+			got = append(got, a)
+		default:
+			return got
+		}
+	}
+}
+
+// expect fails unless exactly the given states were published, in order
+func expect(t *testing.T, alerts <-chan Alert, at string, want ...State) []Alert {
+	t.Helper()
+	got := received(alerts)
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d alerts %+v, want %v", at, len(got), got, want)
+	}
+	for i := range got {
+		if got[i].State != want[i] {
+			t.Fatalf("%s: alert %d is %v, want %v", at, i, got[i].State, want[i])
+		}
+	}
+	return got
+}
+
+func fps(at time.Time, v float64) collector.Snapshot {
+	return collector.Snapshot{Time: at, Stats: model.ServerStats{Mem: model.MemStats{FPS: v}}}
+}
+
+func TestHoldTime(t *testing.T) {
+	e, alerts := newEngine(t, config.AlertRule{Name: "Low FPS", When: "fps < 15", For: config.Duration{Duration: time.Minute}})
+
+	e.evaluate(fps(t0, 10), t0, t0)
+	e.evaluate(fps(t0.Add(30*time.Second), 10), t0, t0.Add(30*time.Second))
+	expect(t, alerts, "30s in")
+
+	// Recovering starts the hold time over
+	e.evaluate(fps(t0.Add(40*time.Second), 20), t0, t0.Add(40*time.Second))
+	e.evaluate(fps(t0.Add(50*time.Second), 10), t0, t0.Add(50*time.Second))
+	e.evaluate(fps(t0.Add(100*time.Second), 10), t0, t0.Add(100*time.Second))
+	expect(t, alerts, "50s after the dip")
+
+	at := t0.Add(110 * time.Second)
+	e.evaluate(fps(at, 9), t0, at)
+	got := expect(t, alerts, "a minute after the dip", Firing)
+	if a := got[0]; a.Since != t0.Add(50*time.Second) || a.Value != 9 || a.Rule != "Low FPS" || a.Severity != defaultSeverity {
+		t.Errorf("alert = %+v", a)
+	}
+	if firing := e.Firing(); len(firing) != 1 {
+		t.Errorf("Firing = %+v, want the low FPS alert", firing)
+	}
+
+	// Holding on doesn't fire again
+	e.evaluate(fps(at.Add(time.Minute), 8), t0, at.Add(time.Minute))
+	expect(t, alerts, "still low")
+}
+
+func TestResolveAndCooldown(t *testing.T) {
+	e, alerts := newEngine(t, config.AlertRule{When: "fps < 15", Cooldown: config.Duration{Duration: 5 * time.Minute}})
+
+	e.evaluate(fps(t0, 10), t0, t0)
+	expect(t, alerts, "dip", Firing)
+
+	at := t0.Add(10 * time.Second)
+	e.evaluate(fps(at, 30), t0, at)
+	got := expect(t, alerts, "recovered", Resolved)
+	if got[0].Value != 30 || got[0].Rule != "fps < 15" {
+		t.Errorf("resolved alert = %+v", got[0])
+	}
+	if firing := e.Firing(); len(firing) != 0 {
+		t.Errorf("Firing = %+v after resolving", firing)
+	}
+
+	// Holds again within the cooldown: held back until it is over
+	for _, after := range []time.Duration{20 * time.Second, 4 * time.Minute} {
+		e.evaluate(fps(t0.Add(after), 10), t0, t0.Add(after))
+	}
+	expect(t, alerts, "within the cooldown")
+
+	at = t0.Add(5 * time.Minute)
+	e.evaluate(fps(at, 10), t0, at)
+	expect(t, alerts, "after the cooldown", Firing)
+}
+
+func TestPlayerStateCleanup(t *testing.T) {
+	e, alerts := newEngine(t, config.AlertRule{When: "player_ping > 300", Cooldown: config.Duration{Duration: time.Minute}})
+	ping := func(at time.Time, players ...model.Player) collector.Snapshot {
+		return collector.Snapshot{Time: at, Players: players}
+	}
+
+	e.evaluate(ping(t0, model.Player{Name: "Newbie", Ping: 400}, model.Player{Name: "Grout", Ping: 30}), t0, t0)
+	got := expect(t, alerts, "lagging", Firing)
+	if got[0].Player != "Newbie" || got[0].Subject() != "player_ping > 300 (Newbie)" {
+		t.Errorf("alert = %+v", got[0])
+	}
+
+	// Newbie left
+	at := t0.Add(10 * time.Second)
+	e.evaluate(ping(at, model.Player{Name: "Grout", Ping: 30}), t0, at)
+	got = expect(t, alerts, "left", Resolved)
+	if got[0].Player != "Newbie" || got[0].Value != 400 {
+		t.Errorf("resolved alert = %+v", got[0])
+	}
+
+	// Kept for the cooldown, then forgotten
+	if len(e.states) != 1 {
+		t.Errorf("%d states during the cooldown, want 1", len(e.states))
+	}
+	at = t0.Add(time.Minute)
+	e.evaluate(ping(at, model.Player{Name: "Grout", Ping: 30}), t0, at)
+	expect(t, alerts, "after the cooldown")
+	if len(e.states) != 0 {
+		t.Errorf("states = %+v, want none", e.states)
+	}
+}
+
+func TestStaleSnapshot(t *testing.T) {
+	e, alerts := newEngine(t, config.AlertRule{When: "fps < 15"})
+
+	// No poll yet
+	e.evaluate(collector.Snapshot{}, time.Time{}, t0)
+	// Last poll long ago
+	at := t0.Add(staleAfter)
+	e.evaluate(fps(t0, 10), t0, at)
+	expect(t, alerts, "stale dip")
+
+	e.evaluate(fps(at, 10), at, at)
+	expect(t, alerts, "fresh dip", Firing)
+
+	// A stale recovery doesn't resolve it either
+	later := at.Add(staleAfter)
+	e.evaluate(fps(at, 10), at, later)
+	expect(t, alerts, "stale")
+	if firing := e.Firing(); len(firing) != 1 {
+		t.Errorf("Firing = %+v, want the alert still firing", firing)
+	}
+}
+
+func TestSecondsSincePoll(t *testing.T) {
+	e, alerts := newEngine(t, config.AlertRule{When: "seconds_since_poll > 30", Severity: "critical"})
+
+	// Before the first answer it counts from Start
+	e.evaluate(collector.Snapshot{}, time.Time{}, t0.Add(30*time.Second))
+	expect(t, alerts, "30s after start")
+	e.evaluate(collector.Snapshot{}, time.Time{}, t0.Add(31*time.Second))
+	got := expect(t, alerts, "31s after start", Firing)
+	if got[0].Value != 31 || got[0].Severity != "critical" {
+		t.Errorf("alert = %+v", got[0])
+	}
+
+	// Keeps counting while the snapshot goes stale
+	answered := t0.Add(40 * time.Second)
+	e.evaluate(fps(t0, 60), answered, answered.Add(time.Second))
+	expect(t, alerts, "answered", Resolved)
+	e.evaluate(fps(t0, 60), answered, answered.Add(6*time.Minute))
+	expect(t, alerts, "silent again", Firing)
+}
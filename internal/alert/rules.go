@@ -0,0 +1,142 @@
+package alert
+
+import (
+	"7dtd-monitor/internal/collector"
+	"7dtd-monitor/internal/config"
+	"7dtd-monitor/internal/model"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrBadRule = errors.New("bad alert rule")
+
+const (
+	defaultCooldown = 5 * time.Minute
+	defaultSeverity = "warning"
+)
+
+// metric reads one value from a snapshot, ok is false if the poll didn't get
+// it. Per-player metrics return one value per player, keyed by name.
+type metric struct {
+	value   func(snap collector.Snapshot) (v float64, ok bool)
+	players func(snap collector.Snapshot) map[string]float64
+}
+
+// always wraps metrics that every poll has
+func always(f func(snap collector.Snapshot) float64) func(collector.Snapshot) (float64, bool) {
+	return func(snap collector.Snapshot) (float64, bool) { return f(snap), true }
+}
+
+// metrics rules can use. seconds_since_poll is handled by the engine since it
+// must keep counting while the server doesn't answer.
+var metrics = map[string]metric{
+	// Older servers leave some mem fields out, they stay at zero
+	"fps": {value: func(s collector.Snapshot) (float64, bool) {
+		return s.Stats.Mem.FPS, s.Stats.Mem.FPS > 0
+	}},
+	"heap_percent": {value: func(s collector.Snapshot) (float64, bool) {
+		mem := s.Stats.Mem
+		if mem.MaxMB == 0 {
+			return 0, false
+		}
+		return mem.HeapMB / mem.MaxMB * 100, true
+	}},
+	"heap_mb": {value: func(s collector.Snapshot) (float64, bool) {
+		return s.Stats.Mem.HeapMB, s.Stats.Mem.HeapMB > 0
+	}},
+	"rss_mb": {value: func(s collector.Snapshot) (float64, bool) {
+		return s.Stats.Mem.RSSMB, s.Stats.Mem.RSSMB > 0
+	}},
+	"chunks":   {value: always(func(s collector.Snapshot) float64 { return float64(s.Stats.Mem.Chunks) })},
+	"players":  {value: always(func(s collector.Snapshot) float64 { return float64(len(s.Players)) })},
+	"zombies":  {value: always(func(s collector.Snapshot) float64 { return float64(s.Counts[model.CategoryZombie]) })},
+	"entities": {value: always(func(s collector.Snapshot) float64 { return float64(len(s.Entities)) })},
+	"avg_ping": {value: always(func(s collector.Snapshot) float64 { return float64(s.AvgPing) })},
+	"player_ping": {players: func(s collector.Snapshot) map[string]float64 {
+		pings := make(map[string]float64, len(s.Players))
+		for _, p := range s.Players {
+			pings[p.Name] = float64(p.Ping)
+		}
+		return pings
+	}},
+}
+
+// Seconds since the server last answered a command
+const metricSinceLastPoll = "seconds_since_poll"
+
+// Metrics lists the metric names rules can use
+func Metrics() []string {
+	names := []string{metricSinceLastPoll}
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var operators = map[string]func(a, b float64) bool{
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+// rule is a config.AlertRule with its condition parsed
+type rule struct {
+	name      string
+	condition string
+	severity  string
+	after     time.Duration // config "for"
+	cooldown  time.Duration
+
+	metric    string
+	op        func(a, b float64) bool
+	threshold float64
+}
+
+func parseRule(r config.AlertRule) (rule, error) {
+	fields := strings.Fields(r.When)
+	if len(fields) != 3 {
+		return rule{}, fmt.Errorf("%w %q: when must look like \"fps < 15\"", ErrBadRule, r.Name)
+	}
+	name, opText, valueText := fields[0], fields[1], fields[2]
+
+	if _, ok := metrics[name]; !ok && name != metricSinceLastPoll {
+		return rule{}, fmt.Errorf("%w %q: unknown metric %q, use one of %s", ErrBadRule, r.Name, name, strings.Join(Metrics(), ", "))
+	}
+	op, ok := operators[opText]
+	if !ok {
+		return rule{}, fmt.Errorf("%w %q: unknown operator %q", ErrBadRule, r.Name, opText)
+	}
+	threshold, err := strconv.ParseFloat(valueText, 64)
+	if err != nil {
+		return rule{}, fmt.Errorf("%w %q: %q is not a number", ErrBadRule, r.Name, valueText)
+	}
+
+	out := rule{
+		name:      r.Name,
+		condition: strings.Join(fields, " "),
+		severity:  r.Severity,
+		after:     r.For.Duration,
+		cooldown:  r.Cooldown.Duration,
+		metric:    name,
+		op:        op,
+		threshold: threshold,
+	}
+	if out.name == "" {
+		out.name = out.condition
+	}
+	if out.severity == "" {
+		out.severity = defaultSeverity
+	}
+	if out.cooldown == 0 {
+		out.cooldown = defaultCooldown
+	}
+	return out, nil
+}
@@ -0,0 +1,213 @@
+// Package api serves the collector's view of the server as JSON. Clients
+// holding the API token can also follow game events, logs and stats as
+// Server-Sent Events, see player IPs and run console commands.
+package api
+
+import (
+	"7dtd-monitor/internal/collector"
+	"7dtd-monitor/internal/events"
+	"7dtd-monitor/internal/model"
+	"7dtd-monitor/internal/telnet"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Server is the HTTP API. Commands share the telnet client's queue with
+// everything else, so clients never need the telnet password.
+type Server struct {
+	client *telnet.Client
+	stream *events.Stream
+	coll   *collector.Collector
+	token  string // Bearer token, the feed and commands are disabled when empty
+}
+
+func New(client *telnet.Client, stream *events.Stream, coll *collector.Collector, token string) *Server {
+	return &Server{client: client, stream: stream, coll: coll, token: token}
+}
+
+// Register adds the API routes to mux
+func (s *Server) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/players", s.players)
+	mux.HandleFunc("GET /api/stats", s.stats)
+	mux.HandleFunc("GET /api/entities", s.entities)
+	mux.HandleFunc("GET /api/time", s.gameTime)
+	mux.HandleFunc("GET /api/events", s.authorized(s.feed))
+	mux.HandleFunc("POST /api/command", s.authorized(s.command))
+}
+
+// BloodMoon is model.BloodMoonStatus with the countdown in seconds
+type BloodMoon struct {
+	Enabled bool    `json:"enabled"`
+	Active  bool    `json:"active"`
+	Day     int     `json:"day"`
+	Seconds float64 `json:"seconds"` // Until it starts, or until it ends while active
+}
+
+func newBloodMoon(bm model.BloodMoonStatus) BloodMoon {
+	return BloodMoon{Enabled: bm.Enabled, Active: bm.Active, Day: bm.Day, Seconds: bm.Until.Seconds()}
+}
+
+// Stats is the body of GET /api/stats
+type Stats struct {
+	Connected     bool                         `json:"connected"`
+	PolledAt      time.Time                    `json:"polled_at"`
+	Host          string                       `json:"host"`
+	GameTime      model.GameTime               `json:"game_time"`
+	BloodMoon     BloodMoon                    `json:"blood_moon"`
+	UptimeSeconds float64                      `json:"uptime_seconds"`
+	FPS           float64                      `json:"fps"`
+	HeapMB        float64                      `json:"heap_mb"`
+	MaxMB         float64                      `json:"max_mb"`
+	RSSMB         float64                      `json:"rss_mb"`
+	Chunks        int                          `json:"chunks"`
+	Players       int                          `json:"players"`
+	AvgPing       int                          `json:"avg_ping"`
+	Entities      map[model.EntityCategory]int `json:"entities"`
+}
+
+// NewStats flattens a snapshot into the JSON stats shape
+func NewStats(snap collector.Snapshot, connected bool) Stats {
+	mem := snap.Stats.Mem
+	return Stats{
+		Connected:     connected,
+		PolledAt:      snap.Time,
+		Host:          snap.Stats.Host,
+		GameTime:      snap.Stats.Time,
+		BloodMoon:     newBloodMoon(snap.BloodMoon),
+		UptimeSeconds: snap.Stats.Uptime.Seconds(),
+		FPS:           mem.FPS,
+		HeapMB:        mem.HeapMB,
+		MaxMB:         mem.MaxMB,
+		RSSMB:         mem.RSSMB,
+		Chunks:        mem.Chunks,
+		Players:       snap.Stats.PlayerCount,
+		AvgPing:       snap.AvgPing,
+		Entities:      snap.Counts,
+	}
+}
+
+// latest returns the last snapshot, or answers 503 if nothing was polled yet
+func (s *Server) latest(w http.ResponseWriter) (collector.Snapshot, bool) {
+	snap := s.coll.Latest()
+	if snap.Time.IsZero() {
+		writeError(w, http.StatusServiceUnavailable, "no data polled yet")
+		return snap, false
+	}
+	return snap, true
+}
+
+// players leaves out IPs unless the request carries the token
+func (s *Server) players(w http.ResponseWriter, r *http.Request) {
+	if snap, ok := s.latest(w); ok {
+		// A copy either way, the snapshot is shared
+		players := make([]model.Player, len(snap.Players))
+		copy(players, snap.Players)
+		if !s.authenticated(r) {
+			for i := range players {
+				players[i].IP = ""
+			}
+		}
+		writeJSON(w, http.StatusOK, players)
+	}
+}
+
+func (s *Server) entities(w http.ResponseWriter, r *http.Request) {
+	if snap, ok := s.latest(w); ok {
+		entities := snap.Entities
+		if entities == nil {
+			entities = []model.Entity{}
+		}
+		writeJSON(w, http.StatusOK, entities)
+	}
+}
+
+func (s *Server) stats(w http.ResponseWriter, r *http.Request) {
+	if snap, ok := s.latest(w); ok {
+		writeJSON(w, http.StatusOK, NewStats(snap, s.coll.Connected()))
+	}
+}
+
+func (s *Server) gameTime(w http.ResponseWriter, r *http.Request) {
+	if snap, ok := s.latest(w); ok {
+		writeJSON(w, http.StatusOK, struct {
+			model.GameTime
+			Text      string    `json:"text"`
+			BloodMoon BloodMoon `json:"blood_moon"`
+		}{snap.Stats.Time, snap.Stats.Time.String(), newBloodMoon(snap.BloodMoon)})
+	}
+}
+
+// commandRequest is the body of POST /api/command
+type commandRequest struct {
+	Command string `json:"command"`
+}
+
+// commandResponse is the reply to POST /api/command
+type commandResponse struct {
+	Output string `json:"output"`
+}
+
+func (s *Server) command(w http.ResponseWriter, r *http.Request) {
+	var req commandRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "body must be {\"command\": \"...\"}")
+		return
+	}
+	// One command per request, a newline would smuggle in a second one
+	cmd := strings.TrimSpace(req.Command)
+	if cmd == "" || strings.ContainsAny(cmd, "\r\n") {
+		writeError(w, http.StatusBadRequest, "command must be a single non-empty line")
+		return
+	}
+
+	raw, err := s.client.SendCommand(cmd)
+	switch {
+	case errors.Is(err, telnet.ErrNotConnected), errors.Is(err, telnet.ErrClosed):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusGatewayTimeout, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, commandResponse{Output: raw})
+}
+
+// authenticated reports whether r has "Authorization: Bearer <token>"
+func (s *Server) authenticated(r *http.Request) bool {
+	if s.token == "" {
+		return false
+	}
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) == 1
+}
+
+// authorized requires the token for next
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token == "" {
+			writeError(w, http.StatusForbidden, "no API token set, start the monitor with -api-token")
+			return
+		}
+		if !s.authenticated(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "missing or wrong API token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"7dtd-monitor/internal/collector"
+	"7dtd-monitor/internal/events"
+	"7dtd-monitor/internal/model"
+	"7dtd-monitor/internal/parser"
+	"7dtd-monitor/internal/telnet"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Proxies drop idle connections, a comment line every so often keeps them open
+const keepAliveInterval = 15 * time.Second
+
+// Message is one entry of the live feed. Type is also sent as the SSE event
+// name, so browsers can addEventListener per type.
+type Message struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data any       `json:"data"`
+}
+
+// Feed message types besides the game events below
+const (
+	TypeStats      = "stats"
+	TypeLog        = "log"
+	TypeConnection = "connection"
+)
+
+// eventTypes names game events on the feed
+var eventTypes = map[events.Type]string{
+	events.PlayerConnected:      "connect",
+	events.PlayerSpawned:        "spawn",
+	events.PlayerDisconnected:   "disconnect",
+	events.PlayerDied:           "death",
+	events.PlayerKilledByPlayer: "kill",
+	events.ChatMessage:          "chat",
+	events.Kick:                 "kick",
+	events.Ban:                  "ban",
+	events.BloodMoonStart:       "blood_moon_start",
+	events.BloodMoonEnd:         "blood_moon_end",
+	events.AirDrop:              "air_drop",
+	events.ServerShutdown:       "shutdown",
+}
+
+// Player is events.Player on the feed
+type Player struct {
+	EntityID   string `json:"entity_id,omitempty"`
+	PlatformID string `json:"platform_id,omitempty"`
+	Name       string `json:"name"`
+}
+
+// GameEvent is the data of game event messages. Only the fields the event
+// type uses are set.
+type GameEvent struct {
+	Player   *Player     `json:"player,omitempty"`
+	Killer   *Player     `json:"killer,omitempty"`
+	Message  string      `json:"message,omitempty"`
+	Channel  string      `json:"channel,omitempty"`
+	Until    string      `json:"until,omitempty"`
+	Reason   string      `json:"reason,omitempty"`
+	Position *model.Vec3 `json:"position,omitempty"`
+	IP       string      `json:"ip,omitempty"`
+	Line     string      `json:"line"`
+}
+
+// LogLine is the data of log messages
+type LogLine struct {
+	Level   string `json:"level"`
+	Source  string `json:"source,omitempty"`
+	Message string `json:"message"`
+	Raw     string `json:"raw"`
+}
+
+// Connection is the data of connection messages
+type Connection struct {
+	State   string     `json:"state"`
+	Error   string     `json:"error,omitempty"`
+	RetryAt *time.Time `json:"retry_at,omitempty"`
+}
+
+func newPlayer(p events.Player) *Player {
+	if p.Name == "" && p.PlatformID == "" {
+		return nil
+	}
+	return &Player{EntityID: p.EntityID, PlatformID: p.PlatformID, Name: p.Name}
+}
+
+func gameEventMessage(ev events.Event) Message {
+	data := GameEvent{
+		Player:  newPlayer(ev.Player),
+		Killer:  newPlayer(ev.Killer),
+		Message: ev.Message,
+		Channel: ev.Channel,
+		Until:   ev.Until,
+		Reason:  ev.Reason,
+		IP:      ev.IP,
+		Line:    ev.Line.Raw,
+	}
+	if ev.Type == events.PlayerSpawned || ev.Type == events.AirDrop {
+		pos := ev.Position
+		data.Position = &pos
+	}
+	return Message{Type: eventTypes[ev.Type], Time: ev.Time, Data: data}
+}
+
+// logMessage reads a raw log line, ok is false for anything else the
+// server printed
+func logMessage(raw string, at time.Time) (Message, bool) {
+	l, ok := parser.ParseLogLine(raw)
+	if !ok {
+		return Message{}, false
+	}
+	data := LogLine{Level: l.Level, Source: l.Source, Message: l.Message, Raw: l.Raw}
+	return Message{Type: TypeLog, Time: at, Data: data}, true
+}
+
+func connectionMessage(ev telnet.Event) Message {
+	data := Connection{State: ev.State.String()}
+	if ev.Err != nil {
+		data.Error = ev.Err.Error()
+	}
+	if !ev.RetryAt.IsZero() {
+		retryAt := ev.RetryAt
+		data.RetryAt = &retryAt
+	}
+	return Message{Type: TypeConnection, Time: ev.Time, Data: data}
+}
+
+// feed streams messages as Server-Sent Events until the client goes away.
+// It needs the token, raw log lines are full of player IPs.
+// ?types=chat,connect,disconnect limits the feed to those types.
+func (s *Server) feed(w http.ResponseWriter, r *http.Request) {
+	wanted := map[string]bool{}
+	if types := r.URL.Query().Get("types"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			wanted[strings.TrimSpace(t)] = true
+		}
+	}
+
+	// Subscribe before answering so nothing published meanwhile is lost
+	lines, cancelLines := s.client.Subscribe()
+	defer cancelLines()
+	gameEvents, cancelEvents := s.stream.Subscribe()
+	defer cancelEvents()
+	updates, cancelUpdates := s.coll.Subscribe()
+	defer cancelUpdates()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	send := func(m Message) error {
+		if len(wanted) > 0 && !wanted[m.Type] {
+			return nil
+		}
+		data, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", m.Type, data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+
+		case ev, ok := <-lines:
+			if !ok {
+				return
+			}
+			switch ev.Type {
+			case telnet.EventLog:
+				if m, ok := logMessage(ev.Line, ev.Time); ok {
+					err = send(m)
+				}
+			case telnet.EventState:
+				err = send(connectionMessage(ev))
+			}
+
+		case ev, ok := <-gameEvents:
+			if !ok {
+				return
+			}
+			err = send(gameEventMessage(ev))
+
+		case u, ok := <-updates:
+			if !ok {
+				return
+			}
+			if u.Type == collector.UpdateStats {
+				err = send(Message{Type: TypeStats, Time: u.Time, Data: NewStats(u.Snapshot, s.coll.Connected())})
+			}
+
+		case <-keepAlive.C:
+			if _, err = fmt.Fprint(w, ": keep-alive\n\n"); err == nil {
+				err = rc.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
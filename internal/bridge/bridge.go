@@ -0,0 +1,192 @@
+// Package bridge relays chat between the game and an external chat, e.g. a
+// web page or a Discord bot, over a pluggable Transport.
+package bridge
+
+import (
+	"7dtd-monitor/internal/config"
+	"7dtd-monitor/internal/events"
+	"7dtd-monitor/internal/telnet"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// A failed transport is restarted after this
+const retryDelay = 10 * time.Second
+
+// Long messages are cut, the game chat box is small anyway
+const maxSayLength = 200
+
+// Message is a chat message going either way
+type Message struct {
+	Author     string    `json:"author"`
+	PlatformID string    `json:"platform_id,omitempty"` // Only for game chat
+	Channel    string    `json:"channel,omitempty"`     // Only for game chat
+	Text       string    `json:"text"`
+	Time       time.Time `json:"time"`
+}
+
+// Transport connects the bridge to the external chat
+type Transport interface {
+	// Send forwards a game chat message
+	Send(ctx context.Context, m Message) error
+	// Receive delivers external messages to out until ctx is done or it
+	// fails. The bridge calls it again after a failure.
+	Receive(ctx context.Context, out chan<- Message) error
+}
+
+// Bridge forwards game chat to a transport and says what comes back in game
+type Bridge struct {
+	// OnError is told about messages that could not be relayed. Set it
+	// before Start, it is called from the bridge's goroutines.
+	OnError func(error)
+
+	client    *telnet.Client
+	stream    *events.Stream
+	transport Transport
+	channels  map[string]bool
+	prefix    string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func New(client *telnet.Client, stream *events.Stream, transport Transport, cfg config.Bridge) *Bridge {
+	channels := make(map[string]bool)
+	for _, c := range cfg.Channels {
+		channels[strings.ToLower(c)] = true
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Bridge{
+		client:    client,
+		stream:    stream,
+		transport: transport,
+		channels:  channels,
+		prefix:    cfg.Prefix,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Start relays in both directions in the background until Stop
+func (b *Bridge) Start() {
+	gameEvents, cancel := b.stream.Subscribe()
+	go func() {
+		defer cancel()
+		b.outbound(gameEvents)
+	}()
+	go b.inbound()
+}
+
+func (b *Bridge) Stop() {
+	b.cancel()
+}
+
+func (b *Bridge) outbound(gameEvents <-chan events.Event) {
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case ev := <-gameEvents:
+			// Entity id -1 is the server itself, which includes our own says
+			if ev.Type != events.ChatMessage || ev.Player.EntityID == "-1" || !b.channels[strings.ToLower(ev.Channel)] {
+				continue
+			}
+			m := Message{
+				Author:     ev.Player.Name,
+				PlatformID: ev.Player.PlatformID,
+				Channel:    ev.Channel,
+				Text:       ev.Message,
+				Time:       ev.Time,
+			}
+			if err := b.transport.Send(b.ctx, m); err != nil && b.ctx.Err() == nil {
+				b.reportError(fmt.Errorf("forwarding chat: %w", err))
+			}
+		}
+	}
+}
+
+func (b *Bridge) inbound() {
+	msgs := make(chan Message, 64)
+	go func() {
+		for {
+			err := b.transport.Receive(b.ctx, msgs)
+			if b.ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				b.reportError(fmt.Errorf("receiving chat: %w", err))
+			}
+			select {
+			case <-time.After(retryDelay):
+			case <-b.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case m := <-msgs:
+			cmd := SayCommand(b.prefix, m)
+			if cmd == "" {
+				continue
+			}
+			if _, err := b.client.SendCommand(cmd); err != nil {
+				b.reportError(fmt.Errorf("relaying chat from %s: %w", m.Author, err))
+			}
+		}
+	}
+}
+
+// SayCommand builds the say command relaying m, e.g.
+// say "[Web] Grout: hello". It is empty for messages with no text.
+func SayCommand(prefix string, m Message) string {
+	author := clean(m.Author)
+	text := clean(m.Text)
+	if text == "" {
+		return ""
+	}
+	if author == "" {
+		author = "Someone"
+	}
+
+	msg := author + ": " + text
+	if prefix != "" {
+		msg = prefix + " " + msg
+	}
+	if r := []rune(msg); len(r) > maxSayLength {
+		msg = string(r[:maxSayLength-3]) + "..."
+	}
+	return `say "` + msg + `"`
+}
+
+// clean makes external text safe to put in a say command: a newline would
+// start a second console command, a double quote would end the argument,
+// and [ff0000] style tags would let outsiders color their text
+func clean(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '"':
+			return '\''
+		case r == '[':
+			return '('
+		case r == ']':
+			return ')'
+		case unicode.IsControl(r):
+			return ' '
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func (b *Bridge) reportError(err error) {
+	if b.OnError != nil {
+		b.OnError(err)
+	}
+}
@@ -0,0 +1,69 @@
+package bridge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSayCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		m      Message
+		want   string
+	}{
+		{
+			name:   "plain",
+			prefix: "[Web]",
+			m:      Message{Author: "Grout", Text: "hello"},
+			want:   `say "[Web] Grout: hello"`,
+		},
+		{
+			name: "no prefix",
+			m:    Message{Author: "Grout", Text: "hello"},
+			want: `say "Grout: hello"`,
+		},
+		{
+			name: "quotes",
+			m:    Message{Author: `"Admin"`, Text: `he said "hi"`},
+			want: `say "'Admin': he said 'hi'"`,
+		},
+		{
+			name: "newline would start another command",
+			m:    Message{Author: "Grout", Text: "hi\r\nshutdown"},
+			want: `say "Grout: hi shutdown"`,
+		},
+		{
+			name: "control characters",
+			m:    Message{Author: "Gr\x00out", Text: "a\tb\x1b[0m"},
+			want: `say "Gr out: a b (0m"`,
+		},
+		{
+			name: "color codes",
+			m:    Message{Author: "[ff0000]Grout[-]", Text: "[00ff00]green"},
+			want: `say "(ff0000)Grout(-): (00ff00)green"`,
+		},
+		{
+			name: "no author",
+			m:    Message{Text: "hello"},
+			want: `say "Someone: hello"`,
+		},
+		{
+			name: "only whitespace",
+			m:    Message{Author: "Grout", Text: " \n\t "},
+			want: "",
+		},
+		{
+			name: "too long",
+			m:    Message{Author: "Grout", Text: strings.Repeat("é", 300)},
+			want: `say "Grout: ` + strings.Repeat("é", maxSayLength-len("Grout: ")-3) + `..."`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SayCommand(tt.prefix, tt.m); got != tt.want {
+				t.Errorf("SayCommand = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package bridge
+
+import (
+	"7dtd-monitor/internal/config"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPTransport POSTs game chat as JSON to SendURL and polls PollURL for
+// messages going the other way. Either URL may be empty for a one-way bridge.
+//
+// A poll is GET PollURL?after=<cursor>, answered with
+//
+//	{"messages": [{"author": "Grout", "text": "hello"}], "cursor": "42"}
+//
+// where cursor is whatever the endpoint wants back next time, so it can
+// return only newer messages. Endpoints without a cursor must return each
+// message once. The first poll only picks up the cursor, so a restart
+// doesn't replay old messages.
+type HTTPTransport struct {
+	sendURL  string
+	pollURL  string
+	token    string
+	interval time.Duration
+	http     *http.Client
+
+	cursor string
+	primed bool // The first poll was done
+}
+
+func NewHTTPTransport(cfg config.Bridge) *HTTPTransport {
+	return &HTTPTransport{
+		sendURL:  cfg.SendURL,
+		pollURL:  cfg.PollURL,
+		token:    cfg.Token,
+		interval: time.Duration(cfg.PollSeconds) * time.Second,
+		http:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// pollResponse is the body PollURL answers with
+type pollResponse struct {
+	Messages []Message `json:"messages"`
+	Cursor   string    `json:"cursor"`
+}
+
+func (t *HTTPTransport) Send(ctx context.Context, m Message) error {
+	if t.sendURL == "" {
+		return nil
+	}
+	body, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.sendURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (t *HTTPTransport) Receive(ctx context.Context, out chan<- Message) error {
+	if t.pollURL == "" {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		msgs, err := t.poll(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			select {
+			case out <- m:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (t *HTTPTransport) poll(ctx context.Context) ([]Message, error) {
+	u, err := url.Parse(t.pollURL)
+	if err != nil {
+		return nil, err
+	}
+	if t.cursor != "" {
+		q := u.Query()
+		q.Set("after", t.cursor)
+		u.RawQuery = q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var pr pollResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&pr); err != nil {
+		return nil, fmt.Errorf("poll response: %w", err)
+	}
+	if pr.Cursor != "" {
+		t.cursor = pr.Cursor
+	}
+	if !t.primed {
+		t.primed = true
+		return nil, nil
+	}
+	return pr.Messages, nil
+}
+
+// do sends req with the token and turns non-2xx answers into errors
+func (t *HTTPTransport) do(req *http.Request) (*http.Response, error) {
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", req.URL.Redacted(), resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,135 @@
+package bridge
+
+import (
+	"7dtd-monitor/internal/config"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// chatEndpoint serves polls from a list of pages, the cursor is the page
+// number. It records the cursor of every poll and every message POSTed.
+type chatEndpoint struct {
+	mu      sync.Mutex
+	pages   []pollResponse
+	cursors []string
+	sent    []Message
+	auth    []string
+}
+
+func newChatEndpoint(t *testing.T, pages ...pollResponse) (*chatEndpoint, *httptest.Server) {
+	ce := &chatEndpoint{pages: pages}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ce.mu.Lock()
+		defer ce.mu.Unlock()
+		ce.auth = append(ce.auth, r.Header.Get("Authorization"))
+
+		if r.Method == http.MethodPost {
+			var m Message
+			if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+				t.Errorf("decoding message: %v", err)
+			}
+			ce.sent = append(ce.sent, m)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		ce.cursors = append(ce.cursors, r.URL.Query().Get("after"))
+		page := pollResponse{}
+		if n := len(ce.cursors) - 1; n < len(ce.pages) {
+			page = ce.pages[n]
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(srv.Close)
+	return ce, srv
+}
+
+func TestReceiveSkipsBacklog(t *testing.T) {
+	ce, srv := newChatEndpoint(t,
+		pollResponse{Messages: []Message{{Author: "Grout", Text: "from before the restart"}}, Cursor: "1"},
+		pollResponse{Messages: []Message{{Author: "Grout", Text: "hello"}}, Cursor: "2"},
+		// Nothing new, the cursor stays
+		pollResponse{},
+	)
+	tr := NewHTTPTransport(config.Bridge{PollURL: srv.URL, Token: "secret", PollSeconds: 1})
+	tr.interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan Message, 10)
+	errs := make(chan error, 1)
+	go func() { errs <- tr.Receive(ctx, out) }()
+
+	select {
+	case m := <-out:
+		if m.Text != "hello" {
+			t.Errorf("received %q, want only messages after the first poll", m.Text)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a message")
+	}
+
+	// Let it poll past the end of the pages
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	// Canceling may cut a poll short
+	if err := <-errs; err != nil && !errors.Is(err, context.Canceled) {
+		t.Errorf("Receive: %v", err)
+	}
+	select {
+	case m := <-out:
+		t.Errorf("unexpected message %+v", m)
+	default:
+	}
+
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	want := []string{"", "1", "2", "2"}
+	for i, w := range want {
+		if i >= len(ce.cursors) || ce.cursors[i] != w {
+			t.Fatalf("polled with cursors %q, want %q first", ce.cursors, want)
+		}
+	}
+	for _, a := range ce.auth {
+		if a != "Bearer secret" {
+			t.Errorf("Authorization = %q", a)
+		}
+	}
+}
+
+func TestReceiveFailsOnErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	tr := NewHTTPTransport(config.Bridge{PollURL: srv.URL, PollSeconds: 1})
+	if err := tr.Receive(context.Background(), make(chan Message)); err == nil {
+		t.Error("Receive returned no error for a 401")
+	}
+}
+
+func TestSend(t *testing.T) {
+	ce, srv := newChatEndpoint(t)
+	tr := NewHTTPTransport(config.Bridge{SendURL: srv.URL})
+
+	m := Message{Author: "Grout", PlatformID: "Steam_76561198012345678", Channel: "Global", Text: "hello", Time: time.Date(2025, 12, 10, 10, 35, 0, 0, time.UTC)}
+	if err := tr.Send(context.Background(), m); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	if len(ce.sent) != 1 || ce.sent[0] != m {
+		t.Errorf("sent %+v, want %+v", ce.sent, m)
+	}
+	if ce.auth[0] != "" {
+		t.Errorf("Authorization = %q without a token", ce.auth[0])
+	}
+}
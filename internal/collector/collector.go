@@ -0,0 +1,275 @@
+// Package collector polls the server over telnet, parses the output and
+// keeps the latest state. The TUI, the headless daemon and the HTTP
+// endpoints all read from the same collector.
+package collector
+
+import (
+	"7dtd-monitor/internal/metrics"
+	"7dtd-monitor/internal/model"
+	"7dtd-monitor/internal/parser"
+	"7dtd-monitor/internal/pubsub"
+	"7dtd-monitor/internal/store"
+	"7dtd-monitor/internal/telnet"
+	"sync"
+	"time"
+)
+
+// Poll intervals. Game prefs, known players, land claims, bans and admins
+// rarely change, so they are polled less often than stats.
+const (
+	StatsInterval  = 2 * time.Second
+	configInterval = 30 * time.Second
+	knownInterval  = time.Minute
+)
+
+// Snapshot is the result of one stats poll
+type Snapshot struct {
+	Time      time.Time
+	Stats     model.ServerStats
+	BloodMoon model.BloodMoonStatus
+	Players   []model.Player
+	Entities  []model.Entity
+	Counts    map[model.EntityCategory]int // Live entities per category
+	AvgPing   int                          // ms, 0 with nobody online
+}
+
+// Collector runs the poll loop. Updates go out to subscribers and the latest
+// stats and prefs can be read at any time.
+type Collector struct {
+	client *telnet.Client
+	known  *store.KnownPlayers // Optional
+	ring   *metrics.Ring       // Optional
+
+	mu           sync.Mutex
+	latest       Snapshot
+	prefs        model.GamePrefs // Empty until the first config poll
+	lastResponse time.Time
+
+	subs pubsub.Hub[Update]
+}
+
+// New creates a collector. known and ring may be nil.
+func New(client *telnet.Client, known *store.KnownPlayers, ring *metrics.Ring) *Collector {
+	return &Collector{
+		client: client,
+		known:  known,
+		ring:   ring,
+	}
+}
+
+// Start runs the poll loop in the background
+func (c *Collector) Start() {
+	go c.loop()
+}
+
+func (c *Collector) loop() {
+	ticker := time.NewTicker(StatsInterval)
+	defer ticker.Stop()
+
+	var lastConfig, lastKnown time.Time
+	for {
+		<-ticker.C
+		// Nothing to poll while the client is reconnecting
+		if !c.Connected() {
+			continue
+		}
+		if time.Since(lastConfig) >= configInterval {
+			c.pollConfig()
+			lastConfig = time.Now()
+		}
+		if time.Since(lastKnown) >= knownInterval {
+			c.pollKnownPlayers()
+			c.pollLandClaims()
+			c.RefreshBans()
+			lastKnown = time.Now()
+		}
+		c.pollStats()
+	}
+}
+
+// Connected reports whether the telnet session is logged in
+func (c *Collector) Connected() bool {
+	state, _ := c.client.State()
+	return state == telnet.StateAuthenticated
+}
+
+// Latest returns the last stats snapshot, zero before the first poll
+func (c *Collector) Latest() Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.latest
+}
+
+// LastResponse returns when the server last answered a command, zero before
+// the first answer. Unlike the snapshot time it stops moving when the server
+// hangs with the connection still up.
+func (c *Collector) LastResponse() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastResponse
+}
+
+// Prefs returns the last polled game prefs
+func (c *Collector) Prefs() model.GamePrefs {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.prefs
+}
+
+// command runs cmd and notes when the server last answered
+func (c *Collector) command(cmd string) (string, error) {
+	raw, err := c.client.SendCommand(cmd)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.lastResponse = time.Now()
+	c.mu.Unlock()
+	return raw, nil
+}
+
+// pollStats takes a snapshot. A failed command skips the whole poll: zero
+// FPS and an empty server would look real to every subscriber.
+func (c *Collector) pollStats() {
+	// 1. Get Time
+	timeStr, err := c.command("gettime")
+	if err != nil {
+		return
+	}
+	gameTime, err := parser.ParseTime(timeStr)
+	if err != nil {
+		return
+	}
+	bloodMoon := gameTime.BloodMoon(model.DefaultBloodMoonFrequency, model.DefaultDayLightLength, model.DefaultDayNightLength)
+	if prefs := c.Prefs(); prefs.Values != nil {
+		bloodMoon = gameTime.BloodMoon(prefs.BloodMoonFrequency, prefs.DayLightLength, prefs.DayNightLength)
+	}
+
+	// 2. Get Mem & FPS
+	memStr, err := c.command("mem")
+	if err != nil {
+		return
+	}
+	mem, err := parser.ParseMem(memStr)
+	if err != nil {
+		return
+	}
+
+	// 3. Get Players
+	playersStr, err := c.command("lp")
+	if err != nil {
+		return
+	}
+	players, _ := parser.ParsePlayers(playersStr)
+
+	// 4. Get Entities
+	entStr, err := c.command("le")
+	if err != nil {
+		return
+	}
+	entities, _ := parser.ParseEntities(entStr)
+	counts := parser.CountByCategory(entities)
+
+	// Calculate Avg Ping
+	var totalPing int
+	for _, p := range players {
+		totalPing += p.Ping
+	}
+	avgPing := 0
+	if len(players) > 0 {
+		avgPing = totalPing / len(players)
+	}
+
+	now := time.Now()
+	snap := Snapshot{
+		Time: now,
+		Stats: model.ServerStats{
+			Host:        c.client.Host,
+			Time:        gameTime,
+			Uptime:      mem.Uptime,
+			Mem:         mem,
+			PlayerCount: len(players),
+		},
+		BloodMoon: bloodMoon,
+		Players:   players,
+		Entities:  entities,
+		Counts:    counts,
+		AvgPing:   avgPing,
+	}
+
+	if c.ring != nil {
+		c.ring.Add(metrics.Sample{
+			Time:    now,
+			FPS:     mem.FPS,
+			HeapMB:  mem.HeapMB,
+			RSSMB:   mem.RSSMB,
+			Chunks:  mem.Chunks,
+			Players: len(players),
+			Zombies: counts[model.CategoryZombie],
+			AvgPing: avgPing,
+		})
+	}
+
+	c.mu.Lock()
+	c.latest = snap
+	c.mu.Unlock()
+	c.subs.Publish(Update{Type: UpdateStats, Time: now, Snapshot: snap})
+}
+
+func (c *Collector) pollConfig() {
+	prefsStr, err := c.command("ggp")
+	if err != nil {
+		return
+	}
+	statsStr, _ := c.command("ggs")
+
+	prefs, err := parser.ParseGamePrefs(prefsStr + "\n" + statsStr)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.prefs = prefs
+	c.mu.Unlock()
+	c.subs.Publish(Update{Type: UpdatePrefs, Time: time.Now(), Prefs: prefs})
+}
+
+func (c *Collector) pollKnownPlayers() {
+	if c.known == nil {
+		return
+	}
+	knownStr, err := c.command("lkp")
+	if err != nil {
+		return
+	}
+	players, _ := parser.ParseKnownPlayers(knownStr)
+	c.known.Update(players)
+
+	// A failed save is passed on, the in-memory store is still current
+	err = c.known.Save()
+	c.subs.Publish(Update{Type: UpdateKnownPlayers, Time: time.Now(), Err: err})
+}
+
+func (c *Collector) pollLandClaims() {
+	claimsStr, err := c.command("llp")
+	if err != nil {
+		return
+	}
+	owners, _ := parser.ParseLandClaims(claimsStr)
+	c.subs.Publish(Update{Type: UpdateLandClaims, Time: time.Now(), Claims: owners})
+}
+
+// RefreshBans polls the ban and admin lists now, e.g. after changing them
+func (c *Collector) RefreshBans() {
+	bansStr, err := c.command("ban list")
+	if err != nil {
+		return
+	}
+	adminsStr, err := c.command("admin list")
+	if err != nil {
+		return
+	}
+	bans, _ := parser.ParseBans(bansStr)
+	admins, _ := parser.ParseAdmins(adminsStr)
+	c.subs.Publish(Update{Type: UpdateBans, Time: time.Now(), Bans: bans, Admins: admins})
+}
@@ -0,0 +1,36 @@
+package collector
+
+import (
+	"7dtd-monitor/internal/model"
+	"time"
+)
+
+// UpdateType says which part of an Update is set
+type UpdateType int
+
+const (
+	UpdateStats        UpdateType = iota + 1 // Snapshot
+	UpdatePrefs                              // Prefs
+	UpdateKnownPlayers                       // The known players store changed, Err if saving failed
+	UpdateLandClaims                         // Claims
+	UpdateBans                               // Bans and Admins
+)
+
+// Update is sent to subscribers after each poll
+type Update struct {
+	Type UpdateType
+	Time time.Time
+
+	Snapshot Snapshot
+	Prefs    model.GamePrefs
+	Claims   []model.LandClaimOwner
+	Bans     []model.Ban
+	Admins   []model.Admin
+	Err      error
+}
+
+// Subscribe returns a channel receiving updates and a function to stop the
+// subscription. See pubsub.Hub.
+func (c *Collector) Subscribe() (<-chan Update, func()) {
+	return c.subs.Subscribe()
+}
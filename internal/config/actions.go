@@ -0,0 +1,94 @@
+// Package config loads optional user settings from the data directory.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// BanUnits are the units "ban add" accepts
+var BanUnits = []string{"minutes", "hours", "days", "weeks", "months", "years"}
+
+// BanDuration is a preset for "ban add", e.g. {7, "days"}
+type BanDuration struct {
+	Amount int    `json:"amount"`
+	Unit   string `json:"unit"` // One of BanUnits
+}
+
+func (d BanDuration) String() string {
+	return fmt.Sprintf("%d %s", d.Amount, d.Unit)
+}
+
+// Actions holds the presets offered by the kick and ban dialogs
+type Actions struct {
+	KickReasons  []string      `json:"kick_reasons"`
+	BanReasons   []string      `json:"ban_reasons"`
+	BanDurations []BanDuration `json:"ban_durations"`
+}
+
+// DefaultActions is used for anything actions.json leaves out
+func DefaultActions() Actions {
+	return Actions{
+		KickReasons: []string{
+			"Kicked by admin",
+			"Please rejoin",
+			"Offensive language",
+			"Server restart",
+		},
+		BanReasons: []string{
+			"Griefing",
+			"Cheating",
+			"Harassment",
+			"Offensive language",
+		},
+		BanDurations: []BanDuration{
+			{1, "hours"},
+			{1, "days"},
+			{7, "days"},
+			{30, "days"},
+			{10, "years"},
+		},
+	}
+}
+
+// LoadActions reads presets from a JSON file onto the defaults, so lists the
+// file leaves out keep their default. A ban duration with an unknown unit is
+// an error. A missing file gives the defaults.
+func LoadActions(path string) (Actions, error) {
+	a := DefaultActions()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+	if err != nil {
+		return Actions{}, err
+	}
+
+	// json would decode ban_durations element by element onto the default
+	// presets, so an entry without an amount would borrow one
+	var file struct {
+		*Actions
+		BanDurations []BanDuration `json:"ban_durations"`
+	}
+	file.Actions = &a
+	if err := json.Unmarshal(data, &file); err != nil {
+		return Actions{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if file.BanDurations != nil {
+		a.BanDurations = file.BanDurations
+	}
+	for _, d := range a.BanDurations {
+		if !slices.Contains(BanUnits, d.Unit) {
+			return Actions{}, fmt.Errorf("%s: ban duration %q: unit must be one of %s", path, d, strings.Join(BanUnits, ", "))
+		}
+		if d.Amount <= 0 {
+			return Actions{}, fmt.Errorf("%s: ban duration %q: amount must be at least 1", path, d)
+		}
+	}
+	return a, nil
+}
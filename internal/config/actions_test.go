@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    func(a *Actions)
+		wantErr string // Part of the error, empty if none
+	}{
+		{
+			name: "kick reasons only",
+			file: `{"kick_reasons": ["AFK"]}`,
+			want: func(a *Actions) { a.KickReasons = []string{"AFK"} },
+		},
+		{
+			name: "durations",
+			file: `{"ban_durations": [{"amount": 2, "unit": "weeks"}]}`,
+			want: func(a *Actions) { a.BanDurations = []BanDuration{{2, "weeks"}} },
+		},
+		{
+			name:    "unknown unit",
+			file:    `{"ban_durations": [{"amount": 1, "unit": "days"}, {"amount": 3, "unit": "day"}]}`,
+			wantErr: `"3 day"`,
+		},
+		{
+			name:    "no amount",
+			file:    `{"ban_durations": [{"unit": "days"}]}`,
+			wantErr: `"0 days"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "actions.json")
+			if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadActions(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadActions error = %v, want one naming %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadActions: %v", err)
+			}
+			want := DefaultActions()
+			tt.want(&want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("LoadActions = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Duration reads "90s" or "2m" style strings from JSON
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"90s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// AlertRule is one rule from alerts.json, e.g.
+//
+//	{"name": "Low FPS", "when": "fps < 15", "for": "60s"}
+type AlertRule struct {
+	Name     string   `json:"name"`
+	When     string   `json:"when"`     // "<metric> <op> <number>"
+	For      Duration `json:"for"`      // How long When must hold before the alert fires
+	Cooldown Duration `json:"cooldown"` // Least time between two firings, 5m if left out
+	Severity string   `json:"severity"` // warning or critical, warning if left out
+}
+
+// Alerts holds the rules from alerts.json
+type Alerts struct {
+	Rules []AlertRule `json:"rules"`
+}
+
+// DefaultAlerts is used when alerts.json is missing or has no rules key
+func DefaultAlerts() Alerts {
+	return Alerts{Rules: []AlertRule{
+		{Name: "Low FPS", When: "fps < 15", For: Duration{time.Minute}},
+		{Name: "Heap almost full", When: "heap_percent > 90", For: Duration{time.Minute}},
+		{Name: "High ping", When: "player_ping > 300", For: Duration{2 * time.Minute}},
+		{Name: "Zombie swarm", When: "zombies > 200"},
+		{Name: "Server not responding", When: "seconds_since_poll > 30", Severity: "critical"},
+	}}
+}
+
+// LoadAlerts reads alert rules from a JSON file. A missing file gives the
+// defaults, "rules": [] turns alerting off.
+func LoadAlerts(path string) (Alerts, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultAlerts(), nil
+	}
+	if err != nil {
+		return Alerts{}, err
+	}
+
+	var a Alerts
+	if err := json.Unmarshal(data, &a); err != nil {
+		return Alerts{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if a.Rules == nil {
+		a.Rules = DefaultAlerts().Rules
+	}
+	return a, nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Bridge holds the chat bridge settings from bridge.json
+type Bridge struct {
+	SendURL     string   `json:"send_url"` // Game chat is POSTed here
+	PollURL     string   `json:"poll_url"` // Messages for the game are fetched from here
+	Token       string   `json:"token"`    // Sent as a Bearer token to both, if set
+	PollSeconds int      `json:"poll_seconds"`
+	Channels    []string `json:"channels"` // Game chat channels to forward
+	Prefix      string   `json:"prefix"`   // Put before relayed messages in game
+}
+
+// Enabled reports whether either direction is configured
+func (b Bridge) Enabled() bool {
+	return b.SendURL != "" || b.PollURL != ""
+}
+
+// DefaultBridge is used for anything bridge.json leaves out. Party and
+// friends chat stay private unless listed in channels.
+func DefaultBridge() Bridge {
+	return Bridge{
+		PollSeconds: 5,
+		Channels:    []string{"Global"},
+		Prefix:      "[Web]",
+	}
+}
+
+// LoadBridge reads chat bridge settings from a JSON file onto the defaults,
+// so keys the file leaves out keep their default and "prefix": "" turns the
+// prefix off. A missing file gives the defaults, which have no URLs and so
+// leave the bridge off.
+func LoadBridge(path string) (Bridge, error) {
+	b := DefaultBridge()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return Bridge{}, err
+	}
+
+	if err := json.Unmarshal(data, &b); err != nil {
+		return Bridge{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if b.PollSeconds <= 0 {
+		return Bridge{}, fmt.Errorf("%s: poll_seconds must be at least 1", path)
+	}
+	return b, nil
+}
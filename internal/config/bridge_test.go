@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadBridge(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    func(b *Bridge)
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			file: `{}`,
+			want: func(b *Bridge) {},
+		},
+		{
+			name: "prefix turned off",
+			file: `{"poll_url": "https://example.com/chat", "prefix": ""}`,
+			want: func(b *Bridge) {
+				b.PollURL = "https://example.com/chat"
+				b.Prefix = ""
+			},
+		},
+		{
+			name: "channels",
+			file: `{"channels": ["Global", "Party"], "poll_seconds": 2}`,
+			want: func(b *Bridge) {
+				b.Channels = []string{"Global", "Party"}
+				b.PollSeconds = 2
+			},
+		},
+		{
+			name:    "zero poll interval",
+			file:    `{"poll_seconds": 0}`,
+			wantErr: true,
+		},
+		{
+			name:    "not json",
+			file:    `poll_seconds = 5`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "bridge.json")
+			if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadBridge(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("LoadBridge = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadBridge: %v", err)
+			}
+			want := DefaultBridge()
+			tt.want(&want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("LoadBridge = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
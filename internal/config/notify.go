@@ -0,0 +1,105 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// NotifyEvent configures one kind of webhook notification. Template is a
+// text/template rendered into the embed description.
+type NotifyEvent struct {
+	Enabled  bool   `json:"enabled"`
+	Title    string `json:"title"`
+	Template string `json:"template"`
+	Color    int    `json:"color"` // Embed side bar, e.g. 0x2ecc71 is 3066993
+}
+
+// Notifications holds the webhook settings from notify.json
+type Notifications struct {
+	WebhookURL   string                 `json:"webhook_url"` // Notifications are off when empty
+	Username     string                 `json:"username"`    // Overrides the webhook's name
+	FPSThreshold float64                `json:"fps_threshold"`
+	MaxPerMinute int                    `json:"max_per_minute"`
+	MaxRetries   int                    `json:"max_retries"`
+	Events       map[string]NotifyEvent `json:"events"`
+}
+
+// Notification kinds, the keys of Notifications.Events
+const (
+	NotifyJoin       = "join"
+	NotifyLeave      = "leave"
+	NotifyDeath      = "death"
+	NotifyBloodMoon  = "blood_moon"
+	NotifyFPSDrop    = "fps_drop"
+	NotifyDisconnect = "disconnect"
+	NotifyReconnect  = "reconnect"
+	NotifyShutdown   = "shutdown"
+	NotifyAlert      = "alert_firing"
+	NotifyResolved   = "alert_resolved"
+)
+
+// DefaultNotifications is used for anything notify.json leaves out
+func DefaultNotifications() Notifications {
+	return Notifications{
+		Username:     "7DTD Monitor",
+		FPSThreshold: 15,
+		MaxPerMinute: 20, // Discord allows 30 per minute per webhook
+		MaxRetries:   3,
+		Events: map[string]NotifyEvent{
+			NotifyJoin:  {true, "Player joined", "**{{.Player}}** joined the server", 0x2ecc71},
+			NotifyLeave: {true, "Player left", "**{{.Player}}** left the server", 0x95a5a6},
+			NotifyDeath: {true, "Player died",
+				"{{if .Killer}}**{{.Player}}** was killed by **{{.Killer}}**{{else}}**{{.Player}}** died{{end}}", 0xe74c3c},
+			NotifyBloodMoon: {true, "Blood moon", "The blood moon is rising on day {{.Day}}", 0x8b0000},
+			NotifyFPSDrop: {true, "Server lagging",
+				"Server FPS dropped to {{printf \"%.1f\" .FPS}} (threshold {{.Threshold}})", 0xe67e22},
+			NotifyDisconnect: {true, "Server unreachable",
+				"Lost the connection to {{.Host}}{{if .Error}}: {{.Error}}{{end}}", 0xe74c3c},
+			NotifyReconnect: {true, "Server back", "Connected to {{.Host}} again", 0x2ecc71},
+			NotifyShutdown:  {true, "Server shutting down", "{{.Message}}", 0xe74c3c},
+			NotifyAlert: {true, "Alert",
+				"**{{.Alert}}**{{if .Player}} for {{.Player}}{{end}} is firing: {{.Condition}}, now {{.Value}}", 0xe67e22},
+			NotifyResolved: {true, "Alert resolved",
+				"**{{.Alert}}**{{if .Player}} for {{.Player}}{{end}} is back to normal", 0x2ecc71},
+		},
+	}
+}
+
+// LoadNotifications reads webhook settings from a JSON file onto the
+// defaults. A missing file gives the defaults, which have no URL and so send
+// nothing. Anything the file leaves out keeps its default, down to single
+// fields of an event, so {"join": {"color": 255}} only recolors joins.
+func LoadNotifications(path string) (Notifications, error) {
+	n := DefaultNotifications()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return n, nil
+	}
+	if err != nil {
+		return Notifications{}, err
+	}
+
+	// Events are decoded one by one, json would replace whole map entries
+	var file struct {
+		*Notifications
+		Events map[string]json.RawMessage `json:"events"`
+	}
+	file.Notifications = &n
+	if err := json.Unmarshal(data, &file); err != nil {
+		return Notifications{}, fmt.Errorf("%s: %w", path, err)
+	}
+	for kind, raw := range file.Events {
+		ev, ok := n.Events[kind]
+		if !ok {
+			return Notifications{}, fmt.Errorf("%s: unknown event %q", path, kind)
+		}
+		if err := json.Unmarshal(raw, &ev); err != nil {
+			return Notifications{}, fmt.Errorf("%s: event %q: %w", path, kind, err)
+		}
+		n.Events[kind] = ev
+	}
+	return n, nil
+}
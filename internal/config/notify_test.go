@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadNotificationsMergesOntoDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.json")
+	file := `{
+		"webhook_url": "https://example.com/hook",
+		"max_retries": 0,
+		"events": {
+			"join": {"color": 255},
+			"leave": {"enabled": false}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadNotifications(path)
+	if err != nil {
+		t.Fatalf("LoadNotifications: %v", err)
+	}
+	defaults := DefaultNotifications()
+
+	if got.WebhookURL != "https://example.com/hook" {
+		t.Errorf("WebhookURL = %q", got.WebhookURL)
+	}
+	if got.MaxRetries != 0 {
+		t.Errorf("MaxRetries = %d, want 0", got.MaxRetries)
+	}
+	if got.Username != defaults.Username || got.MaxPerMinute != defaults.MaxPerMinute {
+		t.Errorf("unset fields lost their defaults: %+v", got)
+	}
+
+	join := defaults.Events[NotifyJoin]
+	join.Color = 255
+	if got.Events[NotifyJoin] != join {
+		t.Errorf("join = %+v, want %+v", got.Events[NotifyJoin], join)
+	}
+	leave := defaults.Events[NotifyLeave]
+	leave.Enabled = false
+	if got.Events[NotifyLeave] != leave {
+		t.Errorf("leave = %+v, want %+v", got.Events[NotifyLeave], leave)
+	}
+	if got.Events[NotifyDeath] != defaults.Events[NotifyDeath] {
+		t.Errorf("death = %+v, want the default", got.Events[NotifyDeath])
+	}
+}
+
+func TestLoadNotificationsRejectsUnknownEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.json")
+	if err := os.WriteFile(path, []byte(`{"events": {"joined": {"enabled": true}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadNotifications(path); err == nil {
+		t.Error("LoadNotifications accepted an unknown event")
+	}
+}
@@ -0,0 +1,239 @@
+// Package events turns server log lines into typed game events.
+package events
+
+import (
+	"7dtd-monitor/internal/model"
+	"7dtd-monitor/internal/parser"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type identifies a game event
+type Type int
+
+const (
+	PlayerConnected Type = iota + 1
+	PlayerSpawned
+	PlayerDisconnected
+	PlayerDied
+	PlayerKilledByPlayer
+	ChatMessage
+	Kick
+	Ban
+	BloodMoonStart
+	BloodMoonEnd
+	AirDrop
+	ServerShutdown
+)
+
+func (t Type) String() string {
+	switch t {
+	case PlayerConnected:
+		return "PlayerConnected"
+	case PlayerSpawned:
+		return "PlayerSpawned"
+	case PlayerDisconnected:
+		return "PlayerDisconnected"
+	case PlayerDied:
+		return "PlayerDied"
+	case PlayerKilledByPlayer:
+		return "PlayerKilledByPlayer"
+	case ChatMessage:
+		return "ChatMessage"
+	case Kick:
+		return "Kick"
+	case Ban:
+		return "Ban"
+	case BloodMoonStart:
+		return "BloodMoonStart"
+	case BloodMoonEnd:
+		return "BloodMoonEnd"
+	case AirDrop:
+		return "AirDrop"
+	case ServerShutdown:
+		return "ServerShutdown"
+	}
+	return "Unknown"
+}
+
+// Player identifies who an event is about. Fields the log line doesn't
+// carry are left empty (or filled in by a Stream).
+type Player struct {
+	EntityID   string
+	PlatformID string // e.g. "Steam_76561198012345678"
+	Name       string
+}
+
+// Event is a single typed game event
+type Event struct {
+	Type   Type
+	Time   time.Time
+	Player Player // Subject of the event
+	Killer Player // Only for PlayerKilledByPlayer
+
+	Message  string     // Chat text, kick or ban reason
+	Channel  string     // Chat target: Global, Friends, Party...
+	Until    string     // Ban expiry as printed by the server
+	Reason   string     // Spawn reason, e.g. JoinMultiplayer, Teleport, Died
+	Position model.Vec3 // Spawn or air drop position
+	IP       string     // Only for PlayerConnected
+
+	Line parser.LogLine
+}
+
+var (
+	// Player connected, entityid=171, name=Grout, pltfmid=Steam_765..., crossid=EOS_..., steamOwner=Steam_765..., ip=10.0.0.5
+	rePlayerConnected = regexp.MustCompile(`^Player connected, (.*)$`)
+	// PlayerSpawnedInWorld (reason: JoinMultiplayer, position: 14, 37, 1240): EntityID=171, PltfmId='Steam_765...', ...
+	rePlayerSpawned = regexp.MustCompile(`^PlayerSpawnedInWorld \(reason: ([^,]*), position: ([^)]*)\): (.*)$`)
+	// Player disconnected: EntityID=171, PltfmId='Steam_765...', CrossId='EOS_...', OwnerID='...', PlayerName='Grout', ClientNumber='1'
+	rePlayerDisconnected = regexp.MustCompile(`^Player disconnected: (.*)$`)
+	// GMSG: Player 'Grout' died
+	rePlayerDied = regexp.MustCompile(`^GMSG: Player '(.*)' died$`)
+	// GMSG: Player 'Victim' killed by 'Killer'
+	rePlayerKilled = regexp.MustCompile(`^GMSG: Player '(.*)' killed by '(.*)'$`)
+	// Chat (from 'Steam_765...', entity id '171', to 'Global'): 'Grout': hello
+	reChat = regexp.MustCompile(`^Chat \(from '([^']*)', entity id '(-?\d+)', to '([^']*)'\): (.*)$`)
+	// Kicking player (Kicked by Console): EntityID=171, PltfmId='Steam_765...', ...
+	reKick = regexp.MustCompile(`^Kicking player(?: \((.*?)\))?: (.*)$`)
+	// Player 'Grout' (Steam_765...) banned until 12/10/2035 10:35:08, reason: griefing
+	reBan = regexp.MustCompile(`^(?:Player '(.*?)' )?\(?([A-Za-z]+_[^\s)]+)\)? (?:is )?banned until (.+?)(?:, reason: (.*))?$`)
+
+	reBloodMoonStart = regexp.MustCompile(`(?i)\bblood ?moon\b.*\b(start|starting|started|begins?|beginning)\b`)
+	reBloodMoonEnd   = regexp.MustCompile(`(?i)\bblood ?moon\b.*\b(end|ending|ended|over)\b`)
+	// AIAirDrop: Spawned supply crate at (-1020.0, 180.0, 870.0), plane is at (-900.0, 200.0, 870.0)
+	reAirDrop  = regexp.MustCompile(`^AIAirDrop: Spawned supply crate\b`)
+	reShutdown = regexp.MustCompile(`(?i)^(server )?(is )?shutting down|^shutdown game`)
+
+	reFields = regexp.MustCompile(`(\w+)=('[^']*'|[^,]*)`)
+	reVec3   = regexp.MustCompile(`\(?\s*(-?[\d.]+),\s*(-?[\d.]+),\s*(-?[\d.]+)\s*\)?`)
+)
+
+// Parse turns a log line into an event. ok is false for lines that are not game events.
+func Parse(l parser.LogLine) (ev Event, ok bool) {
+	ev = Event{Time: l.Time, Line: l}
+	msg := l.Message
+
+	// Command echoes quote whatever an admin typed, e.g. say "blood moon starting",
+	// and warnings or exceptions may name anything
+	if strings.HasPrefix(msg, "Executing command") || l.IsProblem() {
+		return Event{}, false
+	}
+
+	// Chat goes first so player text can never look like another event
+	if m := reChat.FindStringSubmatch(msg); m != nil {
+		ev.Type = ChatMessage
+		ev.Player = Player{PlatformID: m[1], EntityID: m[2]}
+		ev.Channel = m[3]
+		ev.Player.Name, ev.Message = splitChat(m[4])
+		return ev, true
+	}
+
+	if m := rePlayerConnected.FindStringSubmatch(msg); m != nil {
+		f := parseFields(m[1])
+		ev.Type = PlayerConnected
+		ev.Player = Player{EntityID: f["entityid"], PlatformID: f["pltfmid"], Name: f["name"]}
+		ev.IP = f["ip"]
+		return ev, true
+	}
+
+	if m := rePlayerSpawned.FindStringSubmatch(msg); m != nil {
+		ev.Type = PlayerSpawned
+		ev.Reason = m[1]
+		ev.Position = parseVec3(m[2])
+		ev.Player = playerFromFields(parseFields(m[3]))
+		return ev, true
+	}
+
+	if m := rePlayerDisconnected.FindStringSubmatch(msg); m != nil {
+		ev.Type = PlayerDisconnected
+		ev.Player = playerFromFields(parseFields(m[1]))
+		return ev, true
+	}
+
+	if m := rePlayerKilled.FindStringSubmatch(msg); m != nil {
+		ev.Type = PlayerKilledByPlayer
+		ev.Player = Player{Name: m[1]}
+		ev.Killer = Player{Name: m[2]}
+		return ev, true
+	}
+
+	if m := rePlayerDied.FindStringSubmatch(msg); m != nil {
+		ev.Type = PlayerDied
+		ev.Player = Player{Name: m[1]}
+		return ev, true
+	}
+
+	if m := reKick.FindStringSubmatch(msg); m != nil {
+		ev.Type = Kick
+		ev.Message = m[1]
+		ev.Player = playerFromFields(parseFields(m[2]))
+		return ev, true
+	}
+
+	if m := reBan.FindStringSubmatch(msg); m != nil {
+		ev.Type = Ban
+		ev.Player = Player{Name: m[1], PlatformID: m[2]}
+		ev.Until = m[3]
+		ev.Message = m[4]
+		return ev, true
+	}
+
+	switch {
+	case reBloodMoonStart.MatchString(msg):
+		ev.Type = BloodMoonStart
+	case reBloodMoonEnd.MatchString(msg):
+		ev.Type = BloodMoonEnd
+	case reAirDrop.MatchString(msg):
+		ev.Type = AirDrop
+		ev.Position = parseVec3(msg)
+	case reShutdown.MatchString(msg):
+		ev.Type = ServerShutdown
+	default:
+		return Event{}, false
+	}
+	return ev, true
+}
+
+// splitChat separates "'Name': text" (or the older "Name: text") into its parts
+func splitChat(s string) (name, text string) {
+	if strings.HasPrefix(s, "'") {
+		if i := strings.Index(s, "': "); i > 0 {
+			return s[1:i], s[i+3:]
+		}
+	}
+	if i := strings.Index(s, ": "); i >= 0 {
+		return s[:i], s[i+2:]
+	}
+	return "", s
+}
+
+// parseFields reads "Key=value, Key='quoted value'" lists; keys are lowercased
+func parseFields(s string) map[string]string {
+	fields := make(map[string]string)
+	for _, m := range reFields.FindAllStringSubmatch(s, -1) {
+		fields[strings.ToLower(m[1])] = strings.Trim(strings.TrimSpace(m[2]), "'")
+	}
+	return fields
+}
+
+func playerFromFields(f map[string]string) Player {
+	return Player{
+		EntityID:   f["entityid"],
+		PlatformID: f["pltfmid"],
+		Name:       f["playername"],
+	}
+}
+
+func parseVec3(s string) model.Vec3 {
+	m := reVec3.FindStringSubmatch(s)
+	if m == nil {
+		return model.Vec3{}
+	}
+	x, _ := strconv.ParseFloat(m[1], 64)
+	y, _ := strconv.ParseFloat(m[2], 64)
+	z, _ := strconv.ParseFloat(m[3], 64)
+	return model.Vec3{X: x, Y: y, Z: z}
+}
@@ -0,0 +1,195 @@
+package events
+
+import (
+	"7dtd-monitor/internal/model"
+	"7dtd-monitor/internal/parser"
+	"testing"
+)
+
+func logLine(t *testing.T, raw string) parser.LogLine {
+	t.Helper()
+	l, ok := parser.ParseLogLine(raw)
+	if !ok {
+		t.Fatalf("not a log line: %q", raw)
+	}
+	return l
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want Event // Time and Line are not compared
+		ok   bool
+	}{
+		{
+			name: "connected",
+			line: "2025-12-10T10:35:08 1991.171 INF Player connected, entityid=176, name=LateJoiner, pltfmid=Steam_76561199000000002, crossid=EOS_0002abcdef, steamOwner=Steam_76561199000000002, ip=10.0.0.42",
+			want: Event{Type: PlayerConnected, Player: Player{EntityID: "176", PlatformID: "Steam_76561199000000002", Name: "LateJoiner"}, IP: "10.0.0.42"},
+			ok:   true,
+		},
+		{
+			name: "spawned",
+			line: "2025-12-10T10:35:08 1991.171 INF PlayerSpawnedInWorld (reason: JoinMultiplayer, position: -1045, 65, 885): EntityID=176, PltfmId='Steam_76561199000000002', CrossId='EOS_0002abcdef', OwnerID='Steam_76561199000000002', PlayerName='LateJoiner', ClientNumber='4'",
+			want: Event{
+				Type: PlayerSpawned, Player: Player{EntityID: "176", PlatformID: "Steam_76561199000000002", Name: "LateJoiner"},
+				Reason: "JoinMultiplayer", Position: model.Vec3{X: -1045, Y: 65, Z: 885},
+			},
+			ok: true,
+		},
+		{
+			name: "disconnected",
+			line: "2025-12-10T10:35:08 1991.171 INF Player disconnected: EntityID=176, PltfmId='Steam_76561199000000002', CrossId='EOS_0002abcdef', OwnerID='Steam_76561199000000002', PlayerName='LateJoiner', ClientNumber='4'",
+			want: Event{Type: PlayerDisconnected, Player: Player{EntityID: "176", PlatformID: "Steam_76561199000000002", Name: "LateJoiner"}},
+			ok:   true,
+		},
+		{
+			name: "chat",
+			line: "2025-12-10T10:35:08 1991.171 INF Chat (from 'Steam_76561198012345678', entity id '171', to 'Global'): 'Survivor, the (PL)': anyone got a spare wrench?",
+			want: Event{
+				Type: ChatMessage, Player: Player{EntityID: "171", PlatformID: "Steam_76561198012345678", Name: "Survivor, the (PL)"},
+				Channel: "Global", Message: "anyone got a spare wrench?",
+			},
+			ok: true,
+		},
+		{
+			name: "chat that looks like an event",
+			line: "2025-12-10T10:35:08 1991.171 INF Chat (from 'Steam_76561198087654321', entity id '172', to 'Party'): 'ZombieSlayer': GMSG: Player 'Newbie' died",
+			want: Event{
+				Type: ChatMessage, Player: Player{EntityID: "172", PlatformID: "Steam_76561198087654321", Name: "ZombieSlayer"},
+				Channel: "Party", Message: "GMSG: Player 'Newbie' died",
+			},
+			ok: true,
+		},
+		{
+			name: "server chat",
+			line: "2025-12-10T10:35:08 1991.171 INF Chat (from '-non-player-', entity id '-1', to 'Global'): 'Server': restart in 5 minutes",
+			want: Event{
+				Type: ChatMessage, Player: Player{EntityID: "-1", PlatformID: "-non-player-", Name: "Server"},
+				Channel: "Global", Message: "restart in 5 minutes",
+			},
+			ok: true,
+		},
+		{
+			name: "died",
+			line: "2025-12-10T10:35:08 1991.171 INF GMSG: Player 'Newbie' died",
+			want: Event{Type: PlayerDied, Player: Player{Name: "Newbie"}},
+			ok:   true,
+		},
+		{
+			name: "killed by player",
+			line: "2025-12-10T10:35:08 1991.171 INF GMSG: Player 'Newbie' killed by 'ZombieSlayer'",
+			want: Event{Type: PlayerKilledByPlayer, Player: Player{Name: "Newbie"}, Killer: Player{Name: "ZombieSlayer"}},
+			ok:   true,
+		},
+		{
+			name: "kick",
+			line: "2025-12-10T10:35:08 1991.171 INF Kicking player (Kicked by Console): EntityID=175, PltfmId='Steam_76561199000000001', CrossId='EOS_0002cccccccc', OwnerID='Steam_76561199000000001', PlayerName='Newbie', ClientNumber='3'",
+			want: Event{Type: Kick, Player: Player{EntityID: "175", PlatformID: "Steam_76561199000000001", Name: "Newbie"}, Message: "Kicked by Console"},
+			ok:   true,
+		},
+		{
+			name: "ban",
+			line: "2025-12-10T10:35:08 1991.171 INF Player 'Griefer' (Steam_76561197960000001) banned until 12/10/2035 10:35:08, reason: griefing",
+			want: Event{Type: Ban, Player: Player{PlatformID: "Steam_76561197960000001", Name: "Griefer"}, Until: "12/10/2035 10:35:08", Message: "griefing"},
+			ok:   true,
+		},
+		{
+			name: "air drop",
+			line: "2025-12-10T10:35:08 1991.171 INF AIAirDrop: Spawned supply crate at (-1020.0, 180.0, 870.0), plane is at (-900.0, 200.0, 870.0)",
+			want: Event{Type: AirDrop, Position: model.Vec3{X: -1020, Y: 180, Z: 870}},
+			ok:   true,
+		},
+		{
+			name: "blood moon start",
+			line: "2025-12-10T10:35:08 1991.171 INF Blood moon is starting",
+			want: Event{Type: BloodMoonStart},
+			ok:   true,
+		},
+		{
+			name: "blood moon end",
+			line: "2025-12-10T10:35:08 1991.171 INF BloodMoon ended",
+			want: Event{Type: BloodMoonEnd},
+			ok:   true,
+		},
+		{
+			name: "shutdown",
+			line: "2025-12-10T10:35:08 1991.171 INF Shutdown game from Telnet",
+			want: Event{Type: ServerShutdown},
+			ok:   true,
+		},
+		{
+			name: "command echo",
+			line: "2025-12-10T10:35:08 1991.171 INF Executing command 'say \"blood moon starting\"' by Telnet from 10.0.2.12:38754",
+		},
+		{
+			name: "air drop flight path",
+			line: "2025-12-10T10:35:08 1991.171 INF AIAirDrop: Computed flight paths for 1 aircraft",
+		},
+		{
+			name: "other supply crate line",
+			line: "2025-12-10T10:35:08 1991.171 INF Despawned supply crate 806 after 1800s",
+		},
+		{
+			name: "exception naming the blood moon",
+			line: "2025-12-10T10:35:08 1991.171 EXC NullReferenceException in BloodMoon start handler",
+		},
+		{
+			name: "unrelated",
+			line: "2025-12-10T10:35:08 1991.171 INF Time: 32.55m FPS: 14.07 Heap: 1918.7MB Max: 1918.7MB Chunks: 249",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev, ok := Parse(logLine(t, tt.line))
+			if ok != tt.ok {
+				t.Fatalf("Parse ok = %v, want %v (%v)", ok, tt.ok, ev.Type)
+			}
+			ev.Time, ev.Line = tt.want.Time, tt.want.Line
+			if ev != tt.want {
+				t.Errorf("Parse =\n%+v\nwant\n%+v", ev, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamFillsInPlayers(t *testing.T) {
+	s := NewStream(nil)
+	newbie := Player{EntityID: "175", PlatformID: "Steam_76561199000000001", Name: "Newbie"}
+
+	tests := []struct {
+		line       string
+		wantPlayer Player
+		wantKiller Player
+	}{
+		{
+			line:       "2025-12-10T10:35:08 1991.171 INF Player connected, entityid=175, name=Newbie, pltfmid=Steam_76561199000000001, crossid=EOS_0002cccccccc, steamOwner=Steam_76561199000000001, ip=10.0.0.99",
+			wantPlayer: newbie,
+		},
+		{
+			// The killer never connected while we were listening
+			line:       "2025-12-10T10:35:09 1991.171 INF GMSG: Player 'Newbie' killed by 'ZombieSlayer'",
+			wantPlayer: newbie,
+			wantKiller: Player{Name: "ZombieSlayer"},
+		},
+		{
+			line:       "2025-12-10T10:35:10 1991.171 INF Player disconnected: EntityID=175, PltfmId='Steam_76561199000000001', CrossId='EOS_0002cccccccc', OwnerID='Steam_76561199000000001', PlayerName='Newbie', ClientNumber='3'",
+			wantPlayer: newbie,
+		},
+		{
+			// Forgotten after disconnecting
+			line:       "2025-12-10T10:35:11 1991.171 INF GMSG: Player 'Newbie' died",
+			wantPlayer: Player{Name: "Newbie"},
+		},
+	}
+	for _, tt := range tests {
+		ev, ok := s.Process(logLine(t, tt.line))
+		if !ok {
+			t.Fatalf("Process(%q) found no event", tt.line)
+		}
+		if ev.Player != tt.wantPlayer || ev.Killer != tt.wantKiller {
+			t.Errorf("%v: player %+v, killer %+v, want %+v, %+v", ev.Type, ev.Player, ev.Killer, tt.wantPlayer, tt.wantKiller)
+		}
+	}
+}
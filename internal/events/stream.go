@@ -0,0 +1,100 @@
+package events
+
+import (
+	"7dtd-monitor/internal/parser"
+	"7dtd-monitor/internal/pubsub"
+	"7dtd-monitor/internal/telnet"
+	"sync"
+)
+
+// Stream parses the log lines of a telnet client into events and fans them
+// out to subscribers. It remembers players seen in connect and spawn events
+// so that name-only lines (deaths) still carry entity and platform IDs.
+type Stream struct {
+	client *telnet.Client
+
+	mu     sync.Mutex
+	byName map[string]Player
+
+	subs pubsub.Hub[Event]
+}
+
+func NewStream(client *telnet.Client) *Stream {
+	return &Stream{
+		client: client,
+		byName: make(map[string]Player),
+	}
+}
+
+// Start subscribes to the client. Call it before Connect so no lines are missed.
+func (s *Stream) Start() {
+	lines, _ := s.client.Subscribe()
+	go func() {
+		for ev := range lines {
+			if ev.Type != telnet.EventLog {
+				continue
+			}
+			l, ok := parser.ParseLogLine(ev.Line)
+			if !ok {
+				continue
+			}
+			if gameEvent, ok := s.Process(l); ok {
+				s.subs.Publish(gameEvent)
+			}
+		}
+	}()
+}
+
+// Process parses a single line and fills in missing player details.
+func (s *Stream) Process(l parser.LogLine) (Event, bool) {
+	ev, ok := Parse(l)
+	if !ok {
+		return ev, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch ev.Type {
+	case PlayerConnected, PlayerSpawned, ChatMessage:
+		ev.Player = s.remember(ev.Player)
+	case PlayerDisconnected:
+		ev.Player = s.remember(ev.Player)
+		delete(s.byName, ev.Player.Name)
+	default:
+		ev.Player = s.lookup(ev.Player)
+		ev.Killer = s.lookup(ev.Killer)
+	}
+	return ev, true
+}
+
+// remember merges p with what we already know and stores the result
+func (s *Stream) remember(p Player) Player {
+	// Server messages use entity id -1
+	if p.Name == "" || p.EntityID == "-1" {
+		return p
+	}
+	p = s.lookup(p)
+	s.byName[p.Name] = p
+	return p
+}
+
+func (s *Stream) lookup(p Player) Player {
+	known, ok := s.byName[p.Name]
+	if !ok {
+		return p
+	}
+	if p.EntityID == "" {
+		p.EntityID = known.EntityID
+	}
+	if p.PlatformID == "" {
+		p.PlatformID = known.PlatformID
+	}
+	return p
+}
+
+// Subscribe returns a channel receiving game events and a function to stop
+// the subscription. See pubsub.Hub.
+func (s *Stream) Subscribe() (<-chan Event, func()) {
+	return s.subs.Subscribe()
+}
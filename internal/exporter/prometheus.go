@@ -0,0 +1,118 @@
+// Package exporter serves the collector's latest snapshot in the Prometheus
+// text exposition format.
+package exporter
+
+import (
+	"7dtd-monitor/internal/collector"
+	"7dtd-monitor/internal/model"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Every metric name starts with this, Prometheus names can't start with a digit
+const namespace = "sdtd_"
+
+// Handler serves /metrics
+func Handler(c *collector.Collector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		write(w, c)
+	})
+}
+
+func write(w io.Writer, c *collector.Collector) {
+	up := 0.0
+	if c.Connected() {
+		up = 1
+	}
+	gauge(w, "up", "Whether the telnet session is logged in", up)
+
+	snap := c.Latest()
+	if snap.Time.IsZero() {
+		return
+	}
+	mem := snap.Stats.Mem
+	const mb = 1024 * 1024
+
+	gauge(w, "last_poll_timestamp_seconds", "When the stats were last polled", float64(snap.Time.Unix()))
+	gauge(w, "uptime_seconds", "Server uptime", snap.Stats.Uptime.Seconds())
+	gauge(w, "fps", "Server frames per second", mem.FPS)
+	gauge(w, "heap_bytes", "Managed heap in use", mem.HeapMB*mb)
+	gauge(w, "heap_max_bytes", "Managed heap reserved", mem.MaxMB*mb)
+	gauge(w, "rss_bytes", "Resident set size of the server process", mem.RSSMB*mb)
+	gauge(w, "chunks_loaded", "Loaded chunks", float64(mem.Chunks))
+	gauge(w, "chunk_game_objects", "Chunk game objects", float64(mem.CGO))
+	gauge(w, "entities_active", "Active entities as reported by mem", float64(mem.Entities))
+	gauge(w, "entities_total", "All entities as reported by mem", float64(mem.EntitiesTotal))
+	gauge(w, "items", "Dropped items", float64(mem.Items))
+	gauge(w, "players_online", "Players online", float64(snap.Stats.PlayerCount))
+	gauge(w, "ping_average_milliseconds", "Average ping of online players", float64(snap.AvgPing))
+
+	gauge(w, "game_day", "In-game day", float64(snap.Stats.Time.Day))
+	gauge(w, "game_hour", "In-game hour", float64(snap.Stats.Time.Hour))
+	bloodMoon := 0.0
+	if snap.BloodMoon.Active {
+		bloodMoon = 1
+	}
+	gauge(w, "blood_moon_active", "Whether horde night is on", bloodMoon)
+	if snap.BloodMoon.Enabled {
+		gauge(w, "blood_moon_day", "Day of the current or next horde night", float64(snap.BloodMoon.Day))
+		gauge(w, "blood_moon_seconds", "Real time until horde night starts, or until it ends while active", snap.BloodMoon.Until.Seconds())
+	}
+
+	// Entities per category, sorted so scrapes are stable
+	categories := make([]string, 0, len(snap.Counts))
+	for cat := range snap.Counts {
+		categories = append(categories, string(cat))
+	}
+	sort.Strings(categories)
+	header(w, "entities", "Live entities by category")
+	for _, cat := range categories {
+		sample(w, "entities", labels("category", cat), float64(snap.Counts[model.EntityCategory(cat)]))
+	}
+
+	perPlayer := []struct {
+		name, help string
+		value      func(model.Player) int
+	}{
+		{"player_ping_milliseconds", "Ping of each online player", func(p model.Player) int { return p.Ping }},
+		{"player_level", "Level of each online player", func(p model.Player) int { return p.Level }},
+		{"player_health", "Health of each online player", func(p model.Player) int { return p.Health }},
+		{"player_deaths", "Deaths of each online player", func(p model.Player) int { return p.Deaths }},
+		{"player_zombie_kills", "Zombie kills of each online player", func(p model.Player) int { return p.Zombies }},
+	}
+	for _, m := range perPlayer {
+		header(w, m.name, m.help)
+		for _, p := range snap.Players {
+			sample(w, m.name, labels("name", p.Name, "platform_id", p.PlatformID), float64(m.value(p)))
+		}
+	}
+}
+
+func header(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s%s %s\n", namespace, name, help)
+	fmt.Fprintf(w, "# TYPE %s%s gauge\n", namespace, name)
+}
+
+func sample(w io.Writer, name, labels string, v float64) {
+	fmt.Fprintf(w, "%s%s%s %g\n", namespace, name, labels, v)
+}
+
+func gauge(w io.Writer, name, help string, v float64) {
+	header(w, name, help)
+	sample(w, name, "", v)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labels formats name/value pairs as {name="value",...}
+func labels(pairs ...string) string {
+	var parts []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, pairs[i], labelEscaper.Replace(pairs[i+1])))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,168 @@
+// Package headless runs the monitor without a terminal, writing what
+// happens on the server as structured log records.
+package headless
+
+import (
+	"7dtd-monitor/internal/alert"
+	"7dtd-monitor/internal/collector"
+	"7dtd-monitor/internal/events"
+	"7dtd-monitor/internal/model"
+	"7dtd-monitor/internal/parser"
+	"7dtd-monitor/internal/telnet"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Stats are polled every few seconds, logging each poll would drown the events
+const statsLogInterval = time.Minute
+
+// Run logs game events, connection changes, server errors and warnings,
+// alerts, and a stats summary every minute until ctx is done. alerts may be
+// nil. It returns ErrAuthFailed if the server stops accepting the password,
+// since retrying can't fix that.
+func Run(ctx context.Context, client *telnet.Client, stream *events.Stream, coll *collector.Collector, alerts *alert.Engine, logger *slog.Logger) error {
+	lines, cancelLines := client.Subscribe()
+	defer cancelLines()
+	gameEvents, cancelEvents := stream.Subscribe()
+	defer cancelEvents()
+	updates, cancelUpdates := coll.Subscribe()
+	defer cancelUpdates()
+	var fired <-chan alert.Alert // Stays nil without an engine, never ready
+	if alerts != nil {
+		var cancelAlerts func()
+		fired, cancelAlerts = alerts.Subscribe()
+		defer cancelAlerts()
+	}
+
+	logger.Info("monitor started", "host", client.Host, "port", client.Port)
+
+	var lastStats time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("monitor stopped")
+			return nil
+
+		case ev := <-lines:
+			switch ev.Type {
+			case telnet.EventState:
+				logState(logger, ev)
+				if ev.State == telnet.StateAuthFailed {
+					return telnet.ErrAuthFailed
+				}
+			case telnet.EventLog:
+				if l, ok := parser.ParseLogLine(ev.Line); ok && l.IsProblem() {
+					logger.Warn("server log", "level", l.Level, "source", l.Source, "message", l.Message)
+				}
+			}
+
+		case ev := <-gameEvents:
+			logEvent(logger, ev)
+
+		case a := <-fired:
+			logAlert(logger, a)
+
+		case u := <-updates:
+			switch {
+			case u.Type == collector.UpdateStats && time.Since(lastStats) >= statsLogInterval:
+				logStats(logger, u.Snapshot)
+				lastStats = time.Now()
+			case u.Type == collector.UpdateKnownPlayers && u.Err != nil:
+				logger.Error("saving known players failed", "error", u.Err)
+			}
+		}
+	}
+}
+
+func logState(logger *slog.Logger, ev telnet.Event) {
+	attrs := []any{"state", ev.State.String()}
+	if ev.Err != nil {
+		attrs = append(attrs, "error", ev.Err.Error())
+	}
+	if !ev.RetryAt.IsZero() {
+		attrs = append(attrs, "retry_in", time.Until(ev.RetryAt).Round(time.Second).String())
+	}
+
+	switch ev.State {
+	case telnet.StateAuthenticated, telnet.StateConnecting:
+		logger.Info("connection", attrs...)
+	default:
+		logger.Warn("connection", attrs...)
+	}
+}
+
+// logEvent writes a game event with only the fields its type uses
+func logEvent(logger *slog.Logger, ev events.Event) {
+	attrs := []any{"event", ev.Type.String()}
+	if ev.Player.Name != "" || ev.Player.PlatformID != "" {
+		attrs = append(attrs, slog.Group("player",
+			"name", ev.Player.Name,
+			"entity_id", ev.Player.EntityID,
+			"platform_id", ev.Player.PlatformID,
+		))
+	}
+
+	switch ev.Type {
+	case events.PlayerConnected:
+		attrs = append(attrs, "ip", ev.IP)
+	case events.PlayerSpawned:
+		attrs = append(attrs, "reason", ev.Reason, "position", coords(ev.Position))
+	case events.PlayerKilledByPlayer:
+		attrs = append(attrs, slog.Group("killer",
+			"name", ev.Killer.Name,
+			"entity_id", ev.Killer.EntityID,
+			"platform_id", ev.Killer.PlatformID,
+		))
+	case events.ChatMessage:
+		attrs = append(attrs, "channel", ev.Channel, "message", ev.Message)
+	case events.Kick:
+		attrs = append(attrs, "reason", ev.Message)
+	case events.Ban:
+		attrs = append(attrs, "until", ev.Until, "reason", ev.Message)
+	case events.AirDrop:
+		attrs = append(attrs, "position", coords(ev.Position))
+	case events.BloodMoonStart, events.BloodMoonEnd, events.ServerShutdown:
+		attrs = append(attrs, "message", ev.Line.Message)
+	}
+	logger.Info("game event", attrs...)
+}
+
+func logAlert(logger *slog.Logger, a alert.Alert) {
+	attrs := []any{
+		"rule", a.Rule,
+		"state", a.State.String(),
+		"severity", a.Severity,
+		"condition", a.Condition,
+		"value", a.Value,
+		"since", a.Since,
+	}
+	if a.Player != "" {
+		attrs = append(attrs, "player", a.Player)
+	}
+	if a.State == alert.Firing {
+		logger.Warn("alert", attrs...)
+	} else {
+		logger.Info("alert", attrs...)
+	}
+}
+
+func logStats(logger *slog.Logger, snap collector.Snapshot) {
+	mem := snap.Stats.Mem
+	logger.Info("stats",
+		"game_time", snap.Stats.Time.String(),
+		"fps", mem.FPS,
+		"heap_mb", mem.HeapMB,
+		"rss_mb", mem.RSSMB,
+		"chunks", mem.Chunks,
+		"players", snap.Stats.PlayerCount,
+		"zombies", snap.Counts[model.CategoryZombie],
+		"avg_ping_ms", snap.AvgPing,
+		"blood_moon_active", snap.BloodMoon.Active,
+	)
+}
+
+// coords logs a position as [x, y, z]
+func coords(v model.Vec3) []float64 {
+	return []float64{v.X, v.Y, v.Z}
+}
@@ -0,0 +1,82 @@
+// Package metrics keeps a short in-memory history of server health.
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Sample is one stats poll
+type Sample struct {
+	Time    time.Time
+	FPS     float64
+	HeapMB  float64
+	RSSMB   float64
+	Chunks  int
+	Players int
+	Zombies int
+	AvgPing int // ms, 0 with nobody online
+}
+
+// Ring is a fixed size buffer of samples where the oldest are overwritten
+// first. It is safe for concurrent use.
+type Ring struct {
+	mu      sync.Mutex
+	samples []Sample
+	next    int  // Where the next sample goes
+	full    bool // All slots hold a sample
+}
+
+func NewRing(size int) *Ring {
+	if size < 1 {
+		size = 1
+	}
+	return &Ring{samples: make([]Sample, size)}
+}
+
+// Size is how many samples the ring holds once full
+func (r *Ring) Size() int {
+	return len(r.samples)
+}
+
+// Add stores s, dropping the oldest sample if the ring is full
+func (r *Ring) Add(s Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.samples[r.next] = s
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Since returns the samples taken after t, oldest first
+func (r *Ring) Since(t time.Time) []Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ordered []Sample
+	if r.full {
+		ordered = append(ordered, r.samples[r.next:]...)
+	}
+	ordered = append(ordered, r.samples[:r.next]...)
+
+	for i, s := range ordered {
+		if s.Time.After(t) {
+			return ordered[i:]
+		}
+	}
+	return nil
+}
+
+// Last returns the newest sample
+func (r *Ring) Last() (Sample, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full && r.next == 0 {
+		return Sample{}, false
+	}
+	return r.samples[(r.next+len(r.samples)-1)%len(r.samples)], true
+}
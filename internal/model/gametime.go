@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// GameTime is the in-game clock printed by "gettime", e.g. "Day 95, 06:10"
+type GameTime struct {
+	Day    int `json:"day"`
+	Hour   int `json:"hour"`
+	Minute int `json:"minute"`
+}
+
+func (t GameTime) String() string {
+	return fmt.Sprintf("Day %d, %02d:%02d", t.Day, t.Hour, t.Minute)
+}
+
+// minutes counts in-game minutes since Day 0, 00:00
+func (t GameTime) minutes() int {
+	return t.Day*24*60 + t.Hour*60 + t.Minute
+}
+
+// Server defaults for the prefs BloodMoon needs
+const (
+	DefaultBloodMoonFrequency = 7
+	DefaultDayLightLength     = 18 // In-game hours of daylight
+	DefaultDayNightLength     = 60 // Real minutes per in-game day
+)
+
+// BloodMoonStatus describes the current or next horde night
+type BloodMoonStatus struct {
+	Enabled bool // False when BloodMoonFrequency is 0
+	Active  bool
+	Day     int           // Day of the current or next horde night
+	Until   time.Duration // Real time until it starts, or until it ends while Active
+}
+
+// BloodMoon works out horde night timing. Horde night starts at dusk on every
+// frequency'th day and lasts until dawn. dayLightLength is in in-game hours,
+// dayNightLength is the real length of an in-game day in minutes.
+// The server's BloodMoonRange randomization is not taken into account.
+func (t GameTime) BloodMoon(frequency, dayLightLength, dayNightLength int) BloodMoonStatus {
+	if frequency <= 0 {
+		return BloodMoonStatus{}
+	}
+	if dayLightLength <= 0 || dayLightLength >= 24 {
+		dayLightLength = DefaultDayLightLength
+	}
+	if dayNightLength <= 0 {
+		dayNightLength = DefaultDayNightLength
+	}
+
+	// With the default 18h of daylight: dawn 04:00, dusk 22:00
+	dawn := (24-dayLightLength)/2 + 1
+	dusk := dawn + dayLightLength
+
+	// Real time per in-game minute
+	scale := time.Duration(dayNightLength) * time.Minute / (24 * 60)
+	now := t.minutes()
+	status := BloodMoonStatus{Enabled: true}
+
+	switch {
+	case t.Day%frequency == 0 && t.Hour >= dusk:
+		// Evening of horde night
+		status.Active = true
+		status.Day = t.Day
+		status.Until = time.Duration((t.Day+1)*24*60+dawn*60-now) * scale
+	case t.Day > 1 && (t.Day-1)%frequency == 0 && t.Hour < dawn:
+		// Morning after, horde night is still going
+		status.Active = true
+		status.Day = t.Day - 1
+		status.Until = time.Duration(t.Day*24*60+dawn*60-now) * scale
+	default:
+		day := (t.Day + frequency - 1) / frequency * frequency
+		if day == 0 {
+			day = frequency
+		}
+		status.Day = day
+		status.Until = time.Duration(day*24*60+dusk*60-now) * scale
+	}
+	return status
+}
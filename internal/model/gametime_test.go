@@ -0,0 +1,96 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBloodMoon(t *testing.T) {
+	// With the defaults an in-game minute is 2.5s and the night runs 22:00-04:00
+	const minute = 2500 * time.Millisecond
+
+	tests := []struct {
+		name            string
+		now             GameTime
+		frequency       int
+		dayLight, dayNL int
+		want            BloodMoonStatus
+	}{
+		{
+			name: "disabled", now: GameTime{Day: 7, Hour: 23}, frequency: 0,
+			want: BloodMoonStatus{},
+		},
+		{
+			name: "day 0", now: GameTime{Day: 0}, frequency: 7,
+			want: BloodMoonStatus{Enabled: true, Day: 7, Until: (7*24*60 + 22*60) * minute},
+		},
+		{
+			// Day 0 is no horde night, so there is no morning after on day 1
+			name: "day 1 before dawn", now: GameTime{Day: 1, Hour: 2}, frequency: 7,
+			want: BloodMoonStatus{Enabled: true, Day: 7, Until: (6*24*60 + 20*60) * minute},
+		},
+		{
+			name: "minute before dusk", now: GameTime{Day: 7, Hour: 21, Minute: 59}, frequency: 7,
+			want: BloodMoonStatus{Enabled: true, Day: 7, Until: minute},
+		},
+		{
+			name: "dusk", now: GameTime{Day: 7, Hour: 22}, frequency: 7,
+			want: BloodMoonStatus{Enabled: true, Active: true, Day: 7, Until: 6 * 60 * minute},
+		},
+		{
+			name: "morning after", now: GameTime{Day: 8, Hour: 3, Minute: 59}, frequency: 7,
+			want: BloodMoonStatus{Enabled: true, Active: true, Day: 7, Until: minute},
+		},
+		{
+			name: "dawn after", now: GameTime{Day: 8, Hour: 4}, frequency: 7,
+			want: BloodMoonStatus{Enabled: true, Day: 14, Until: (6*24*60 + 18*60) * minute},
+		},
+		{
+			name: "every day, noon", now: GameTime{Day: 3, Hour: 12}, frequency: 1,
+			want: BloodMoonStatus{Enabled: true, Day: 3, Until: 10 * 60 * minute},
+		},
+		{
+			name: "every day, night", now: GameTime{Day: 3, Hour: 23}, frequency: 1,
+			want: BloodMoonStatus{Enabled: true, Active: true, Day: 3, Until: 5 * 60 * minute},
+		},
+		{
+			name: "every day, morning after", now: GameTime{Day: 4, Hour: 2}, frequency: 1,
+			want: BloodMoonStatus{Enabled: true, Active: true, Day: 3, Until: 2 * 60 * minute},
+		},
+		{
+			name: "every day, day 1", now: GameTime{Day: 1, Hour: 2}, frequency: 1,
+			want: BloodMoonStatus{Enabled: true, Day: 1, Until: 20 * 60 * minute},
+		},
+		{
+			// 13h of daylight: dawn 06:00, dusk 19:00
+			name: "odd daylight, dusk", now: GameTime{Day: 7, Hour: 19}, frequency: 7, dayLight: 13,
+			want: BloodMoonStatus{Enabled: true, Active: true, Day: 7, Until: 11 * 60 * minute},
+		},
+		{
+			name: "odd daylight, before dusk", now: GameTime{Day: 7, Hour: 18, Minute: 59}, frequency: 7, dayLight: 13,
+			want: BloodMoonStatus{Enabled: true, Day: 7, Until: minute},
+		},
+		{
+			name: "odd daylight, morning after", now: GameTime{Day: 8, Hour: 5}, frequency: 7, dayLight: 13,
+			want: BloodMoonStatus{Enabled: true, Active: true, Day: 7, Until: 60 * minute},
+		},
+		{
+			name: "bad daylight uses default", now: GameTime{Day: 7, Hour: 22}, frequency: 7, dayLight: 24,
+			want: BloodMoonStatus{Enabled: true, Active: true, Day: 7, Until: 6 * 60 * minute},
+		},
+		{
+			name: "two hour days", now: GameTime{Day: 7, Hour: 22}, frequency: 7, dayNL: 120,
+			want: BloodMoonStatus{Enabled: true, Active: true, Day: 7, Until: 6 * 60 * 2 * minute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.now.BloodMoon(tt.frequency, tt.dayLight, tt.dayNL)
+			if got != tt.want {
+				t.Errorf("%v.BloodMoon(%d, %d, %d) = %+v, want %+v",
+					tt.now, tt.frequency, tt.dayLight, tt.dayNL, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,28 +2,155 @@ package model
 
 import "time"
 
-// Player represents a single connected player
+// Player represents a single player, usually a connected one
 type Player struct {
-	ID          string
-	Name        string
-	Level       int
-	Health      int
-	Deaths      int
-	Zombies     int
-	PlayerKills int // "players=X" in output
-	Score       int
-	Ping        int
-	SteamID     string
-	IP          string
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Level       int    `json:"level"`
+	Health      int    `json:"health"`
+	Deaths      int    `json:"deaths"`
+	Zombies     int    `json:"zombie_kills"`
+	PlayerKills int    `json:"player_kills"` // "players=X" in output
+	Score       int    `json:"score"`
+	Ping        int    `json:"ping"`
+	SteamID     string `json:"steam_id,omitempty"` // Kept for older servers, same as PlatformID on newer ones
+	IP          string `json:"ip"`
+
+	PlatformID      string `json:"platform_id"`      // e.g. "Steam_76561198012345678"
+	CrossplatformID string `json:"crossplatform_id"` // e.g. "EOS_0002..."
+	Position        Vec3   `json:"position"`
+	Rotation        Vec3   `json:"rotation"`
+	Remote          bool   `json:"remote"` // False for a player hosting a listen server
+	Online          bool   `json:"online"`
+}
+
+// MemStats is the parsed output of the "mem" command, e.g.
+// "Time: 32.55m FPS: 14.07 Heap: 1918.7MB Max: 1918.7MB Chunks: 249 CGO: 23 Ply: 1 Zom: 0 Ent: 3 (3) Items: 0 CO: 1 RSS: 2924.3MB"
+// Sizes are in MB.
+type MemStats struct {
+	Uptime        time.Duration // "Time"
+	FPS           float64
+	HeapMB        float64
+	MaxMB         float64 // Heap size reserved by the runtime
+	Chunks        int     // Loaded chunks
+	CGO           int     // Chunk game objects
+	Players       int     // "Ply"
+	Zombies       int     // "Zom"
+	Entities      int     // "Ent", active entities
+	EntitiesTotal int     // Number in parentheses after "Ent"
+	Items         int
+	CO            int // Chunk observers
+	RSSMB         float64
 }
 
 // ServerStats holds the aggregate state of the server
 type ServerStats struct {
 	Host        string
-	Time        string // In-game time
+	Time        GameTime // In-game time
 	Uptime      time.Duration
-	HeapUsed    string
-	HeapMax     string
-	Fps         string
+	Mem         MemStats
 	PlayerCount int
 }
+
+// Vec3 is a world position or rotation as printed by the server, e.g. "(-1050.5, 65.0, 890.3)"
+type Vec3 struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// EntityCategory groups 7DTD entity classes
+type EntityCategory string
+
+const (
+	CategoryZombie      EntityCategory = "zombie"
+	CategoryAnimal      EntityCategory = "animal"
+	CategoryPlayer      EntityCategory = "player"
+	CategoryVehicle     EntityCategory = "vehicle"
+	CategoryDrone       EntityCategory = "drone"
+	CategoryItem        EntityCategory = "item"
+	CategorySupplyCrate EntityCategory = "supply crate"
+	CategoryOther       EntityCategory = "other"
+)
+
+// Entity is a single line of "le" output, e.g.
+// "1. id=33134, [type=EntityAnimalRabbit, name=animalChicken, id=33134], pos=(42.4, 37.1, 1238.4), rot=(0.0, 41.3, 0.0), lifetime=float.Max, remote=False, dead=False, health=10"
+type Entity struct {
+	ID       string         `json:"id"`
+	Class    string         `json:"class"` // Entity class, e.g. "EntityZombie", "EntityAnimalRabbit"
+	Name     string         `json:"name"`  // Entity definition, e.g. "zombieBoe", "animalChicken"
+	Category EntityCategory `json:"category"`
+	Position Vec3           `json:"position"`
+	Rotation Vec3           `json:"rotation"`
+	Lifetime float64        `json:"lifetime"` // Seconds, "float.Max" is stored as math.MaxFloat32
+	Remote   bool           `json:"remote"`
+	Dead     bool           `json:"dead"`
+	Health   int            `json:"health"`
+}
+
+// GamePrefs is the server configuration from "getgamepref" and "getgamestat"
+type GamePrefs struct {
+	GameName           string
+	WorldName          string // "GameWorld"
+	Difficulty         int    // "GameDifficulty", 0-5
+	DayNightLength     int    // Real minutes per in-game day
+	DayLightLength     int    // In-game hours of daylight
+	BloodMoonFrequency int    // Days between horde nights, 0 disables them
+	BloodMoonRange     int
+	MaxPlayers         int // "ServerMaxPlayerCount"
+	LootAbundance      int // Percent
+	LootRespawnDays    int
+
+	LandClaimCount                     int
+	LandClaimSize                      int
+	LandClaimDeadZone                  int
+	LandClaimExpiryTime                int // Days offline before claims expire
+	LandClaimDecayMode                 int
+	LandClaimOnlineDurabilityModifier  int
+	LandClaimOfflineDurabilityModifier int
+
+	// Every value as printed, keyed by its full name, e.g. "GamePref.GameWorld"
+	Values map[string]string
+}
+
+// KnownPlayer is a player the server has seen at least once, from
+// "listknownplayers" plus what the monitor remembers between runs.
+type KnownPlayer struct {
+	Name            string    `json:"name"`
+	EntityID        string    `json:"entity_id"`
+	PlatformID      string    `json:"platform_id"`
+	CrossplatformID string    `json:"crossplatform_id"`
+	IP              string    `json:"ip"`
+	Online          bool      `json:"online"`
+	Playtime        int       `json:"playtime"` // Seconds
+	LastOnline      time.Time `json:"last_online"`
+
+	// Kept by the known players store
+	FirstSeen time.Time `json:"first_seen"` // When the monitor first saw this player
+	IPs       []string  `json:"ips"`        // Every distinct IP seen, oldest first
+}
+
+// LandClaimOwner is one player's entry in "listlandclaims" output
+type LandClaimOwner struct {
+	Name       string
+	PlatformID string
+	Active     bool    // "protected", false once the owner has been offline past LandClaimExpiryTime
+	Hardiness  float64 // "current hardiness multiplier"
+	Claims     []Vec3  // Keystone block positions
+}
+
+// Ban is an entry of "ban list"
+type Ban struct {
+	PlatformID string
+	Name       string
+	Reason     string
+	Until      time.Time // Zero if the server's date format wasn't understood
+	UntilRaw   string    // As printed by the server
+}
+
+// Admin is a user entry of "admin list"
+type Admin struct {
+	PlatformID string
+	Name       string
+	Level      int // 0 is full access, 1000 is a regular player
+}
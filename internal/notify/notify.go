@@ -0,0 +1,259 @@
+// Package notify posts server happenings to a Discord-compatible webhook.
+package notify
+
+import (
+	"7dtd-monitor/internal/config"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ErrQueueFull is passed to OnError when notifications come faster than the
+// rate limit lets them out
+var ErrQueueFull = errors.New("notification queue full")
+
+// Discord caps embed descriptions at 4096 characters
+const maxDescription = 4096
+
+// Data is what templates can use. Fields that don't apply to an event are empty.
+type Data struct {
+	Time      time.Time
+	Host      string
+	Player    string
+	Killer    string
+	Message   string
+	Day       int
+	FPS       float64
+	Threshold float64
+	Error     string
+
+	// Alerts
+	Alert     string
+	Severity  string
+	Condition string
+	Value     string // Formatted like the condition's number
+}
+
+// Embed is a Discord embed
+type Embed struct {
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description"`
+	Color       int       `json:"color,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Payload is the JSON body posted to the webhook
+type Payload struct {
+	Username        string          `json:"username,omitempty"`
+	Embeds          []Embed         `json:"embeds"`
+	AllowedMentions allowedMentions `json:"allowed_mentions"`
+}
+
+// Player names and chat can contain @everyone, nothing we post should ping
+type allowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+type kind struct {
+	title string
+	color int
+	tmpl  *template.Template
+}
+
+// Notifier renders events with their templates and posts them one at a time,
+// no faster than the configured rate
+type Notifier struct {
+	// OnError is told about notifications that could not be delivered. Set it
+	// before Start and Watch, it is called from their goroutines.
+	OnError func(error)
+
+	url        string
+	username   string
+	threshold  float64
+	interval   time.Duration // Between posts
+	maxRetries int
+	backoff    time.Duration // First retry delay, doubled each attempt
+	kinds      map[string]kind
+	http       *http.Client
+
+	queue chan Payload
+	done  chan struct{}
+}
+
+// New parses the templates of the enabled events. Nothing is sent until Start.
+func New(cfg config.Notifications) (*Notifier, error) {
+	n := &Notifier{
+		url:        cfg.WebhookURL,
+		username:   cfg.Username,
+		threshold:  cfg.FPSThreshold,
+		interval:   time.Minute / time.Duration(max(cfg.MaxPerMinute, 1)),
+		maxRetries: cfg.MaxRetries,
+		backoff:    time.Second,
+		kinds:      make(map[string]kind),
+		http:       &http.Client{Timeout: 10 * time.Second},
+		queue:      make(chan Payload, 100),
+		done:       make(chan struct{}),
+	}
+	for name, ev := range cfg.Events {
+		if !ev.Enabled {
+			continue
+		}
+		tmpl, err := template.New(name).Parse(ev.Template)
+		if err != nil {
+			return nil, fmt.Errorf("template for %s: %w", name, err)
+		}
+		n.kinds[name] = kind{title: ev.Title, color: ev.Color, tmpl: tmpl}
+	}
+	return n, nil
+}
+
+// Start posts queued notifications in the background until Stop
+func (n *Notifier) Start() {
+	go n.sendLoop()
+}
+
+// Stop drops anything still queued
+func (n *Notifier) Stop() {
+	close(n.done)
+}
+
+// Notify queues the notification for event kind. Disabled kinds are ignored.
+// It never blocks, if the queue is full the notification is dropped.
+func (n *Notifier) Notify(eventKind string, data Data) {
+	k, ok := n.kinds[eventKind]
+	if !ok {
+		return
+	}
+	if data.Time.IsZero() {
+		data.Time = time.Now()
+	}
+
+	var desc strings.Builder
+	if err := k.tmpl.Execute(&desc, data); err != nil {
+		n.reportError(fmt.Errorf("template for %s: %w", eventKind, err))
+		return
+	}
+	text := desc.String()
+	if r := []rune(text); len(r) > maxDescription {
+		text = string(r[:maxDescription-3]) + "..."
+	}
+
+	p := Payload{
+		Username:        n.username,
+		Embeds:          []Embed{{Title: k.title, Description: text, Color: k.color, Timestamp: data.Time.UTC()}},
+		AllowedMentions: allowedMentions{Parse: []string{}},
+	}
+	select {
+	case n.queue <- p:
+	default:
+		n.reportError(fmt.Errorf("%s: %w", eventKind, ErrQueueFull))
+	}
+}
+
+func (n *Notifier) sendLoop() {
+	var next time.Time
+	for {
+		select {
+		case p := <-n.queue:
+			if wait := time.Until(next); wait > 0 && !n.sleep(wait) {
+				return
+			}
+			if err := n.deliver(p); err != nil {
+				n.reportError(err)
+			}
+			next = time.Now().Add(n.interval)
+		case <-n.done:
+			return
+		}
+	}
+}
+
+// deliver posts p, retrying server errors with backoff and waiting out
+// 429s as long as the webhook asks
+func (n *Notifier) deliver(p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	delay := n.backoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := n.post(body)
+		if err == nil {
+			return nil
+		}
+		if attempt >= n.maxRetries || retryAfter < 0 {
+			return fmt.Errorf("webhook: %w", err)
+		}
+		wait := delay
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		if !n.sleep(wait) {
+			return nil
+		}
+		delay *= 2
+	}
+}
+
+// post sends one request. retryAfter is negative if retrying can't help, and
+// set to what the webhook asked for on a 429.
+func (n *Notifier) post(body []byte) (retryAfter time.Duration, err error) {
+	resp, err := n.http.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	switch {
+	case resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return rateLimitWait(resp.Header, msg), fmt.Errorf("rate limited")
+	case resp.StatusCode >= 500:
+		return 0, fmt.Errorf("%s", resp.Status)
+	}
+	// Bad URL, deleted webhook or a payload Discord rejects
+	return -1, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// rateLimitWait reads how long a 429 asks us to wait. Discord sends
+// retry_after in seconds in the body, others use the Retry-After header.
+func rateLimitWait(h http.Header, body []byte) time.Duration {
+	var limited struct {
+		RetryAfter float64 `json:"retry_after"`
+	}
+	if json.Unmarshal(body, &limited) == nil && limited.RetryAfter > 0 {
+		return time.Duration(limited.RetryAfter * float64(time.Second))
+	}
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return time.Second
+}
+
+// sleep waits d, returning false if the notifier was stopped meanwhile
+func (n *Notifier) sleep(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-n.done:
+		return false
+	}
+}
+
+func (n *Notifier) reportError(err error) {
+	if n.OnError != nil {
+		n.OnError(err)
+	}
+}
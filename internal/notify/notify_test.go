@@ -0,0 +1,230 @@
+package notify
+
+import (
+	"7dtd-monitor/internal/config"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhook stands in for Discord: it records payloads and answers with the
+// queued statuses, then 204 once they run out
+type webhook struct {
+	mu       sync.Mutex
+	statuses []int
+	payloads []Payload
+	times    []time.Time
+	got      chan struct{}
+}
+
+func newWebhook(t *testing.T, statuses ...int) (*webhook, *httptest.Server) {
+	wh := &webhook{statuses: statuses, got: make(chan struct{}, 100)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p Payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+
+		wh.mu.Lock()
+		wh.payloads = append(wh.payloads, p)
+		wh.times = append(wh.times, time.Now())
+		status := http.StatusNoContent
+		if len(wh.statuses) > 0 {
+			status, wh.statuses = wh.statuses[0], wh.statuses[1:]
+		}
+		wh.mu.Unlock()
+
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 0.05, "global": false}`))
+		} else {
+			w.WriteHeader(status)
+		}
+		wh.got <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+	return wh, srv
+}
+
+// wait blocks until the webhook was hit n more times
+func (wh *webhook) wait(t *testing.T, n int) {
+	t.Helper()
+	for range n {
+		select {
+		case <-wh.got:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the webhook")
+		}
+	}
+}
+
+func testNotifier(t *testing.T, url string, maxPerMinute int) (*Notifier, chan error) {
+	cfg := config.DefaultNotifications()
+	cfg.WebhookURL = url
+	cfg.MaxPerMinute = maxPerMinute
+	n, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.backoff = 10 * time.Millisecond
+
+	errs := make(chan error, 10)
+	n.OnError = func(err error) { errs <- err }
+	n.Start()
+	t.Cleanup(n.Stop)
+	return n, errs
+}
+
+func TestNotifyPostsEmbed(t *testing.T) {
+	wh, srv := newWebhook(t)
+	n, _ := testNotifier(t, srv.URL, 60)
+
+	at := time.Date(2025, 12, 10, 10, 35, 8, 0, time.UTC)
+	n.Notify(config.NotifyDeath, Data{Time: at, Player: "Grout", Killer: "ZombieSlayer"})
+	wh.wait(t, 1)
+
+	p := wh.payloads[0]
+	if p.Username != "7DTD Monitor" {
+		t.Errorf("username = %q", p.Username)
+	}
+	if p.AllowedMentions.Parse == nil || len(p.AllowedMentions.Parse) != 0 {
+		t.Errorf("allowed_mentions.parse = %v, want empty", p.AllowedMentions.Parse)
+	}
+	if len(p.Embeds) != 1 {
+		t.Fatalf("got %d embeds", len(p.Embeds))
+	}
+	e := p.Embeds[0]
+	want := Embed{Title: "Player died", Description: "**Grout** was killed by **ZombieSlayer**", Color: 0xe74c3c, Timestamp: at}
+	if e != want {
+		t.Errorf("embed = %+v, want %+v", e, want)
+	}
+}
+
+func TestNotifyIgnoresDisabledEvents(t *testing.T) {
+	wh, srv := newWebhook(t)
+	cfg := config.DefaultNotifications()
+	cfg.WebhookURL = srv.URL
+	cfg.Events[config.NotifyJoin] = config.NotifyEvent{Enabled: false}
+	n, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.Start()
+	t.Cleanup(n.Stop)
+
+	n.Notify(config.NotifyJoin, Data{Player: "Grout"})
+	n.Notify(config.NotifyLeave, Data{Player: "Grout"})
+	wh.wait(t, 1)
+
+	if got := wh.payloads[0].Embeds[0].Title; got != "Player left" {
+		t.Errorf("first notification = %q, want the leave", got)
+	}
+}
+
+func TestNewRejectsBadTemplate(t *testing.T) {
+	cfg := config.DefaultNotifications()
+	cfg.Events[config.NotifyJoin] = config.NotifyEvent{Enabled: true, Template: "{{.Player"}
+	if _, err := New(cfg); err == nil {
+		t.Error("New accepted a broken template")
+	}
+}
+
+func TestRetriesServerErrorsAndRateLimits(t *testing.T) {
+	wh, srv := newWebhook(t, http.StatusBadGateway, http.StatusTooManyRequests)
+	n, errs := testNotifier(t, srv.URL, 60)
+
+	n.Notify(config.NotifyJoin, Data{Player: "Grout"})
+	wh.wait(t, 3)
+
+	// The 429 asked for 50ms
+	if gap := wh.times[2].Sub(wh.times[1]); gap < 50*time.Millisecond {
+		t.Errorf("retried after %v, before retry_after", gap)
+	}
+	select {
+	case err := <-errs:
+		t.Errorf("unexpected error: %v", err)
+	default:
+	}
+}
+
+func TestGivesUpAfterMaxRetries(t *testing.T) {
+	wh, srv := newWebhook(t, 500, 500, 500, 500, 500)
+	n, errs := testNotifier(t, srv.URL, 60)
+
+	n.Notify(config.NotifyJoin, Data{Player: "Grout"})
+	wh.wait(t, 4) // First try and 3 retries
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("nil error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no error reported")
+	}
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+	if len(wh.payloads) != 4 {
+		t.Errorf("%d attempts, want 4", len(wh.payloads))
+	}
+}
+
+func TestDoesNotRetryClientErrors(t *testing.T) {
+	wh, srv := newWebhook(t, http.StatusNotFound)
+	n, errs := testNotifier(t, srv.URL, 60)
+
+	n.Notify(config.NotifyJoin, Data{Player: "Grout"})
+	wh.wait(t, 1)
+
+	select {
+	case <-errs:
+	case <-time.After(time.Second):
+		t.Fatal("no error reported")
+	}
+	select {
+	case <-wh.got:
+		t.Error("retried a 404")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	wh, srv := newWebhook(t)
+	// 600 a minute is one every 100ms
+	n, _ := testNotifier(t, srv.URL, 600)
+
+	for range 3 {
+		n.Notify(config.NotifyJoin, Data{Player: "Grout"})
+	}
+	wh.wait(t, 3)
+
+	for i := 1; i < 3; i++ {
+		if gap := wh.times[i].Sub(wh.times[i-1]); gap < 90*time.Millisecond {
+			t.Errorf("post %d came %v after the previous one", i, gap)
+		}
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	cfg := config.DefaultNotifications()
+	cfg.WebhookURL = "http://127.0.0.1:0"
+	n, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got error
+	n.OnError = func(err error) { got = err }
+
+	// Not started, so nothing drains the queue
+	for range cap(n.queue) + 1 {
+		n.Notify(config.NotifyJoin, Data{Player: "Grout"})
+	}
+	if !errors.Is(got, ErrQueueFull) {
+		t.Errorf("error = %v, want ErrQueueFull", got)
+	}
+}
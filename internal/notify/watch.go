@@ -0,0 +1,144 @@
+package notify
+
+import (
+	"7dtd-monitor/internal/alert"
+	"7dtd-monitor/internal/collector"
+	"7dtd-monitor/internal/config"
+	"7dtd-monitor/internal/events"
+	"7dtd-monitor/internal/telnet"
+	"time"
+)
+
+// A lagging server hovers around the threshold, one FPS notice per this is plenty
+const fpsCooldown = 5 * time.Minute
+
+// Watch turns game events, connection changes and stats polls into
+// notifications, in the background
+func (n *Notifier) Watch(client *telnet.Client, stream *events.Stream, coll *collector.Collector) {
+	lines, cancelLines := client.Subscribe()
+	gameEvents, cancelEvents := stream.Subscribe()
+	updates, cancelUpdates := coll.Subscribe()
+
+	go func() {
+		defer cancelLines()
+		defer cancelEvents()
+		defer cancelUpdates()
+
+		w := watcher{n: n, host: client.Host, connected: coll.Connected()}
+		for {
+			select {
+			case <-n.done:
+				return
+			case ev := <-lines:
+				if ev.Type == telnet.EventState {
+					w.state(ev)
+				}
+			case ev := <-gameEvents:
+				w.gameEvent(ev)
+			case u := <-updates:
+				if u.Type == collector.UpdateStats {
+					w.stats(u.Snapshot)
+				}
+			}
+		}
+	}()
+}
+
+// watcher remembers just enough to notify on changes rather than on every poll
+type watcher struct {
+	n    *Notifier
+	host string
+
+	connected bool
+	polled    bool // A snapshot was seen, so bloodMoon is meaningful
+	bloodMoon bool
+	lastFPS   time.Time
+}
+
+func (w *watcher) state(ev telnet.Event) {
+	switch ev.State {
+	case telnet.StateAuthenticated:
+		if !w.connected {
+			w.n.Notify(config.NotifyReconnect, Data{Time: ev.Time, Host: w.host})
+		}
+		w.connected = true
+	case telnet.StateDisconnected, telnet.StateAuthFailed:
+		// The client retries on its own, only the first failure is news
+		if w.connected {
+			data := Data{Time: ev.Time, Host: w.host}
+			if ev.Err != nil {
+				data.Error = ev.Err.Error()
+			}
+			w.n.Notify(config.NotifyDisconnect, data)
+		}
+		w.connected = false
+	}
+}
+
+func (w *watcher) gameEvent(ev events.Event) {
+	data := Data{Time: ev.Time, Host: w.host, Player: ev.Player.Name}
+	switch ev.Type {
+	case events.PlayerConnected:
+		w.n.Notify(config.NotifyJoin, data)
+	case events.PlayerDisconnected:
+		w.n.Notify(config.NotifyLeave, data)
+	case events.PlayerDied:
+		w.n.Notify(config.NotifyDeath, data)
+	case events.PlayerKilledByPlayer:
+		data.Killer = ev.Killer.Name
+		w.n.Notify(config.NotifyDeath, data)
+	case events.ServerShutdown:
+		data.Message = ev.Line.Message
+		w.n.Notify(config.NotifyShutdown, data)
+	}
+}
+
+func (w *watcher) stats(snap collector.Snapshot) {
+	data := Data{Time: snap.Time, Host: w.host}
+
+	// The first poll only sets the baseline, a monitor started mid horde
+	// night shouldn't announce it
+	bm := snap.BloodMoon
+	if w.polled && bm.Active && !w.bloodMoon {
+		data.Day = bm.Day
+		w.n.Notify(config.NotifyBloodMoon, data)
+	}
+	w.bloodMoon = bm.Active
+	w.polled = true
+
+	// FPS is 0 when mem couldn't be read, that's not lag
+	fps := snap.Stats.Mem.FPS
+	if fps > 0 && fps < w.n.threshold && time.Since(w.lastFPS) >= fpsCooldown {
+		data.FPS, data.Threshold = fps, w.n.threshold
+		w.n.Notify(config.NotifyFPSDrop, data)
+		w.lastFPS = time.Now()
+	}
+}
+
+// WatchAlerts posts alerts as they fire and resolve, in the background
+func (n *Notifier) WatchAlerts(e *alert.Engine) {
+	alerts, cancel := e.Subscribe()
+	go func() {
+		defer cancel()
+		for {
+			select {
+			case <-n.done:
+				return
+			case a := <-alerts:
+				data := Data{
+					Time:      a.Time,
+					Player:    a.Player,
+					Alert:     a.Rule,
+					Severity:  a.Severity,
+					Condition: a.Condition,
+					Value:     a.FormatValue(),
+				}
+				kind := config.NotifyAlert
+				if a.State == alert.Resolved {
+					kind = config.NotifyResolved
+				}
+				n.Notify(kind, data)
+			}
+		}
+	}()
+}
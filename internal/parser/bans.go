@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"7dtd-monitor/internal/model"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// "  2035-12-10 10:35:08 - Steam_76561198012345678 (Grout) - griefing"
+	reBanEntry = regexp.MustCompile(`^(.+?) - ([A-Za-z]+_\S+) \((.*?)\)(?: - (.*))?$`)
+	// "      0: Steam_76561198012345678 (Grout, Grout)"
+	reAdminEntry = regexp.MustCompile(`^(-?\d+): (\S+)(?: \((.*)\))?$`)
+)
+
+// Servers print ban expiry in the host's locale
+var banTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"1/2/2006 3:04:05 PM",
+	"01/02/2006 15:04:05",
+	"02.01.2006 15:04:05",
+}
+
+// ParseBans parses "ban list" output
+func ParseBans(output string) ([]model.Ban, error) {
+	output = sanitizeOutput(output)
+	var bans []model.Ban
+
+	for _, line := range strings.Split(output, "\n") {
+		m := reBanEntry.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		b := model.Ban{
+			UntilRaw:   m[1],
+			PlatformID: m[2],
+			Name:       m[3],
+			Reason:     m[4],
+		}
+		for _, layout := range banTimeLayouts {
+			if t, err := time.ParseInLocation(layout, m[1], time.Local); err == nil {
+				b.Until = t
+				break
+			}
+		}
+		bans = append(bans, b)
+	}
+	return bans, nil
+}
+
+// ParseAdmins parses the user section of "admin list" output. Group and
+// command permissions are ignored.
+func ParseAdmins(output string) ([]model.Admin, error) {
+	output = sanitizeOutput(output)
+	var admins []model.Admin
+
+	inUsers := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Defined ") {
+			inUsers = strings.Contains(line, "User Permissions")
+			continue
+		}
+		if !inUsers {
+			continue
+		}
+
+		m := reAdminEntry.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		level, _ := strconv.Atoi(m[1])
+		admins = append(admins, model.Admin{PlatformID: m[2], Name: adminName(m[3]), Level: level})
+	}
+	return admins, nil
+}
+
+// adminName picks the stored name out of "(Player name if online, stored
+// name)". Names can contain ", " themselves, so look for two equal halves.
+func adminName(s string) string {
+	if strings.HasPrefix(s, ", ") {
+		return s[2:]
+	}
+	for i := 0; i+2 <= len(s); i++ {
+		if s[i:i+2] == ", " && s[:i] == s[i+2:] {
+			return s[:i]
+		}
+	}
+	return s
+}
@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"7dtd-monitor/internal/model"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseEntities parses "le" output into entities. Lines that don't look
+// like entities (totals, logs) are skipped.
+func ParseEntities(output string) ([]model.Entity, error) {
+	output = sanitizeOutput(output)
+	var entities []model.Entity
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.Contains(line, "id=") {
+			continue
+		}
+
+		e := model.Entity{}
+		for _, field := range splitFields(line) {
+			// "[type=EntityZombie, name=zombieBoe, id=171]"
+			if strings.HasPrefix(field, "[") && strings.HasSuffix(field, "]") {
+				e.Class, e.Name = parseEntityType(field[1 : len(field)-1])
+				continue
+			}
+
+			key, val, ok := splitKeyValue(field)
+			if !ok {
+				continue
+			}
+			switch key {
+			case "id":
+				e.ID = val
+			case "pos":
+				e.Position, _ = ParseVec3(val)
+			case "rot":
+				e.Rotation, _ = ParseVec3(val)
+			case "lifetime":
+				if val == "float.Max" {
+					e.Lifetime = math.MaxFloat32
+				} else {
+					e.Lifetime, _ = strconv.ParseFloat(val, 64)
+				}
+			case "remote":
+				e.Remote = parseBool(val)
+			case "dead":
+				e.Dead = parseBool(val)
+			case "health":
+				e.Health, _ = strconv.Atoi(val)
+			}
+		}
+
+		// Player list lines ("1. id=171, Name") have no class
+		if e.ID == "" || e.Class == "" {
+			continue
+		}
+		e.Category = ClassifyEntity(e.Class, e.Name)
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+// Vehicle classes, "EntityVJeep", "EntityVGyroCopter" etc. are matched by prefix
+var vehicleClasses = []string{
+	"EntityVehicle", "EntityBicycle", "EntityMinibike", "EntityMotorcycle",
+}
+
+// ClassifyEntity maps a 7DTD entity class (and, where the class is ambiguous,
+// its name) to a category.
+func ClassifyEntity(class, name string) model.EntityCategory {
+	lowerName := strings.ToLower(name)
+
+	switch {
+	case strings.HasPrefix(class, "EntityPlayer"):
+		return model.CategoryPlayer
+	case strings.HasPrefix(class, "EntityZombie"), class == "EntityVulture":
+		return model.CategoryZombie
+	case class == "EntityEnemyAnimal":
+		// Wolves and bears, but also zombie dogs and bears
+		if strings.HasPrefix(lowerName, "zombie") || strings.HasPrefix(lowerName, "animalzombie") {
+			return model.CategoryZombie
+		}
+		return model.CategoryAnimal
+	case strings.HasPrefix(class, "EntityAnimal"):
+		return model.CategoryAnimal
+	case strings.HasPrefix(class, "EntityDrone"):
+		return model.CategoryDrone
+	case class == "EntitySupplyCrate":
+		return model.CategorySupplyCrate
+	case class == "EntityItem", class == "EntityBackpack", class == "EntityLootContainer":
+		return model.CategoryItem
+	case isVehicleClass(class):
+		return model.CategoryVehicle
+	}
+	return model.CategoryOther
+}
+
+func isVehicleClass(class string) bool {
+	for _, v := range vehicleClasses {
+		if strings.HasPrefix(class, v) {
+			return true
+		}
+	}
+	// "EntityV" followed by an upper case letter
+	rest := strings.TrimPrefix(class, "EntityV")
+	return rest != class && rest != "" && rest[0] >= 'A' && rest[0] <= 'Z'
+}
+
+// CountByCategory tallies entities per category, skipping dead ones
+func CountByCategory(entities []model.Entity) map[model.EntityCategory]int {
+	counts := make(map[model.EntityCategory]int)
+	for _, e := range entities {
+		if e.Dead {
+			continue
+		}
+		counts[e.Category]++
+	}
+	return counts
+}
+
+// parseEntityType reads class and name from the bracketed group. Player
+// names may contain commas, so the name runs up to the last ", id=" like in
+// ParsePlayers.
+func parseEntityType(group string) (class, name string) {
+	head, rest, ok := strings.Cut(group, ", name=")
+	if !ok {
+		return strings.TrimPrefix(head, "type="), ""
+	}
+	if i := strings.LastIndex(rest, ", id="); i >= 0 {
+		rest = rest[:i]
+	}
+	return strings.TrimPrefix(head, "type="), rest
+}
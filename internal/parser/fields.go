@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"7dtd-monitor/internal/model"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// splitFields splits a comma separated line, keeping "(x, y, z)" tuples and
+// "[...]" groups in one piece.
+func splitFields(line string) []string {
+	var fields []string
+	depth := 0
+	start := 0
+	for i, r := range line {
+		switch r {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				fields = append(fields, strings.TrimSpace(line[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(fields, strings.TrimSpace(line[start:]))
+}
+
+// splitKeyValue splits "key=value" into a lowercased key and trimmed value.
+// A leading list index like "1. " is dropped from the key.
+func splitKeyValue(field string) (key, value string, ok bool) {
+	kv := strings.SplitN(field, "=", 2)
+	if len(kv) != 2 {
+		return "", "", false
+	}
+	key = strings.ToLower(strings.TrimSpace(kv[0]))
+	key = strings.TrimLeft(key, "0123456789. ")
+	return key, strings.TrimSpace(kv[1]), true
+}
+
+var reVec3 = regexp.MustCompile(`^\(?\s*(-?[\d.]+),\s*(-?[\d.]+),\s*(-?[\d.]+)\s*\)?$`)
+
+// ParseVec3 parses "(x, y, z)" with or without the parentheses
+func ParseVec3(s string) (model.Vec3, bool) {
+	m := reVec3.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return model.Vec3{}, false
+	}
+	x, _ := strconv.ParseFloat(m[1], 64)
+	y, _ := strconv.ParseFloat(m[2], 64)
+	z, _ := strconv.ParseFloat(m[3], 64)
+	return model.Vec3{X: x, Y: y, Z: z}, true
+}
+
+// parseBool reads the server's "True"/"False"
+func parseBool(s string) bool {
+	return strings.EqualFold(s, "true")
+}
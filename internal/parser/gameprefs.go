@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"7dtd-monitor/internal/model"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// "GamePref.BloodMoonFrequency = 7" or "GameStat.LandClaimCount = 5"
+var reGamePref = regexp.MustCompile(`^(GamePref|GameStat)\.(\w+)\s*=\s*(.*)$`)
+
+// ParseGamePrefs parses "ggp" and/or "ggs" output (both can be concatenated).
+// Typed fields prefer the GamePref value over the GameStat of the same name.
+func ParseGamePrefs(output string) (model.GamePrefs, error) {
+	output = sanitizeOutput(output)
+	prefs := model.GamePrefs{Values: make(map[string]string)}
+
+	for _, line := range strings.Split(output, "\n") {
+		m := reGamePref.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		prefs.Values[m[1]+"."+m[2]] = strings.TrimSpace(m[3])
+	}
+	if len(prefs.Values) == 0 {
+		return prefs, fmt.Errorf("no game prefs in output")
+	}
+
+	get := func(name string) string {
+		if v, ok := prefs.Values["GamePref."+name]; ok {
+			return v
+		}
+		return prefs.Values["GameStat."+name]
+	}
+	getInt := func(name string) int {
+		i, _ := strconv.Atoi(get(name))
+		return i
+	}
+
+	prefs.GameName = get("GameName")
+	prefs.WorldName = get("GameWorld")
+	prefs.Difficulty = getInt("GameDifficulty")
+	prefs.DayNightLength = getInt("DayNightLength")
+	prefs.DayLightLength = getInt("DayLightLength")
+	prefs.BloodMoonFrequency = getInt("BloodMoonFrequency")
+	prefs.BloodMoonRange = getInt("BloodMoonRange")
+	prefs.MaxPlayers = getInt("ServerMaxPlayerCount")
+	prefs.LootAbundance = getInt("LootAbundance")
+	prefs.LootRespawnDays = getInt("LootRespawnDays")
+	prefs.LandClaimCount = getInt("LandClaimCount")
+	prefs.LandClaimSize = getInt("LandClaimSize")
+	prefs.LandClaimDeadZone = getInt("LandClaimDeadZone")
+	prefs.LandClaimExpiryTime = getInt("LandClaimExpiryTime")
+	prefs.LandClaimDecayMode = getInt("LandClaimDecayMode")
+	prefs.LandClaimOnlineDurabilityModifier = getInt("LandClaimOnlineDurabilityModifier")
+	prefs.LandClaimOfflineDurabilityModifier = getInt("LandClaimOfflineDurabilityModifier")
+
+	return prefs, nil
+}
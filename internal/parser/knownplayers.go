@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"7dtd-monitor/internal/model"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// "1. Grout, id=171, pltfmid=Steam_765..., crossid=EOS_..., online=False, ip=10.0.0.5, playtime=1234 m, seen=2025-12-01 19:22"
+// The name comes first and may contain commas, so it is cut off at ", id=".
+var reKnownPlayerHead = regexp.MustCompile(`^\d+\.\s*(.*?),\s*id=`)
+
+const seenLayout = "2006-01-02 15:04"
+
+// ParseKnownPlayers parses "lkp" output
+func ParseKnownPlayers(output string) ([]model.KnownPlayer, error) {
+	output = sanitizeOutput(output)
+	var players []model.KnownPlayer
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		head := reKnownPlayerHead.FindStringSubmatchIndex(line)
+		if head == nil {
+			continue
+		}
+
+		p := model.KnownPlayer{Name: line[head[2]:head[3]]}
+		// Keep "id=" for the field loop
+		rest := line[head[1]-len("id="):]
+
+		for _, field := range splitFields(rest) {
+			key, val, ok := splitKeyValue(field)
+			if !ok {
+				continue
+			}
+			switch key {
+			case "id":
+				p.EntityID = val
+			case "pltfmid":
+				p.PlatformID = val
+			case "steamid":
+				if p.PlatformID == "" {
+					p.PlatformID = "Steam_" + val
+				}
+			case "crossid":
+				p.CrossplatformID = val
+			case "online":
+				p.Online = parseBool(val)
+			case "ip":
+				p.IP = val
+			case "playtime":
+				// "1234 m"
+				minutes, _ := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(val, "m")))
+				p.Playtime = minutes * 60
+			case "seen":
+				p.LastOnline, _ = time.ParseInLocation(seenLayout, val, time.Local)
+			}
+		}
+
+		if p.PlatformID == "" {
+			continue
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
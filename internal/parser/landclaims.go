@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"7dtd-monitor/internal/model"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Player "Grout (Steam_76561198012345678)" owns 2 keystones (protected: True, current hardiness multiplier: 1)
+//
+//	(14, 37, 1240)
+//	(100, 40, 1300)
+var reClaimOwner = regexp.MustCompile(`^Player "(.*) \(([^()]+)\)" owns \d+ keystones \(protected: (\w+), current hardiness multiplier: ([\d.]+)\)`)
+
+// ParseLandClaims parses "llp" output. Claim positions follow their owner line.
+func ParseLandClaims(output string) ([]model.LandClaimOwner, error) {
+	output = sanitizeOutput(output)
+	var owners []model.LandClaimOwner
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+
+		if m := reClaimOwner.FindStringSubmatch(line); m != nil {
+			hardiness, _ := strconv.ParseFloat(m[4], 64)
+			owners = append(owners, model.LandClaimOwner{
+				Name:       m[1],
+				PlatformID: m[2],
+				Active:     parseBool(m[3]),
+				Hardiness:  hardiness,
+			})
+			continue
+		}
+
+		if len(owners) == 0 {
+			continue
+		}
+		if pos, ok := ParseVec3(line); ok {
+			last := &owners[len(owners)-1]
+			last.Claims = append(last.Claims, pos)
+		}
+	}
+	return owners, nil
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogLine is a single structured server log line, e.g.
+// "2025-12-10T10:35:08 1991.171 INF Executing command 'gettime' by Telnet from 10.0.2.12:38754"
+type LogLine struct {
+	Time    time.Time // Server wall clock, the log carries no zone so it is read as local time
+	Uptime  float64   // Seconds since the server started
+	Level   string    // INF, WRN, ERR or EXC
+	Source  string    // Leading "[Tag]" of the message without brackets, if any
+	Message string    // Everything after the level (and source)
+	Raw     string
+}
+
+const logTimeLayout = "2006-01-02T15:04:05"
+
+var (
+	reLogLine   = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}) (\d+(?:\.\d+)?) (INF|WRN|ERR|EXC) ?(.*)$`)
+	reLogSource = regexp.MustCompile(`^\[([^\]]+)\]\s*(.*)$`)
+)
+
+// ParseLogLine parses a raw line. ok is false if the line is not a log line.
+func ParseLogLine(line string) (l LogLine, ok bool) {
+	line = strings.TrimRight(line, "\r\n")
+	m := reLogLine.FindStringSubmatch(line)
+	if m == nil {
+		return LogLine{}, false
+	}
+
+	ts, err := time.ParseInLocation(logTimeLayout, m[1], time.Local)
+	if err != nil {
+		return LogLine{}, false
+	}
+	uptime, _ := strconv.ParseFloat(m[2], 64)
+
+	l = LogLine{
+		Time:    ts,
+		Uptime:  uptime,
+		Level:   m[3],
+		Message: m[4],
+		Raw:     line,
+	}
+	if s := reLogSource.FindStringSubmatch(l.Message); s != nil {
+		l.Source = s[1]
+		l.Message = s[2]
+	}
+	return l, true
+}
+
+// IsProblem reports whether the line is a warning, error or exception
+func (l LogLine) IsProblem() bool {
+	return l.Level == "WRN" || l.Level == "ERR" || l.Level == "EXC"
+}
@@ -2,9 +2,12 @@ package parser
 
 import (
 	"7dtd-monitor/internal/model"
+	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // SplitLogs separates actual command output from server log lines
@@ -13,21 +16,8 @@ func SplitLogs(output string) (cleanData string, logs []string) {
 	var cleanLines []string
 
 	for _, line := range lines {
-		// Log lines typically start with timestamp, or are "INF Executing..."
-		// 7DTD Logs: "2025-..." or "INF ..."
-		// Robust check: date format or INF/WRN/ERR
-		isLog := false
-		if strings.Contains(line, "INF Executing command") {
-			isLog = true
-		} else if len(line) > 20 && line[4] == '-' && line[7] == '-' && line[10] == 'T' {
-			// 2025-12-10T... crude ISO check
-			isLog = true
-		} else if strings.Contains(line, "INF") || strings.Contains(line, "WRN") || strings.Contains(line, "ERR") {
-			// Common log levels
-			isLog = true
-		}
-
-		if isLog {
+		line = strings.TrimRight(line, "\r")
+		if _, ok := ParseLogLine(line); ok {
 			logs = append(logs, line)
 		} else {
 			if strings.TrimSpace(line) != "" {
@@ -44,6 +34,14 @@ func sanitizeOutput(output string) string {
 	return clean
 }
 
+// The name has no key and may contain commas or parentheses, so it is cut
+// out between "id=N, " and the first known key before the rest is split.
+// e.g. "1. id=171, Survivor, the (PL), pos=(-1050.5, 65.0, 890.3), rot=(...), remote=True, ..."
+var (
+	rePlayerHead = regexp.MustCompile(`^(?:\d+\.\s*)?id=(\d+),\s*`)
+	rePlayerKeys = regexp.MustCompile(`,\s*(pos|rot|remote|health|deaths|zombies|players|score|level|steamid|pltfmid|crossid|ip|ping)=`)
+)
+
 // Robust Key-Value parser instead of strict Regex
 func ParsePlayers(output string) ([]model.Player, error) {
 	output = sanitizeOutput(output)
@@ -54,37 +52,27 @@ func ParsePlayers(output string) ([]model.Player, error) {
 		line = strings.TrimSpace(line)
 		// Check for standard ID start
 		// e.g. "1. id=..."
-		if !strings.Contains(line, "id=") {
+		head := rePlayerHead.FindStringSubmatch(line)
+		if head == nil {
 			continue
 		}
 
-		p := model.Player{}
-		stats := make(map[string]string)
-
-		// 7DTD format is essentially comma separated values, usually "key=value"
-		// But the name field often doesn't have a key, e.g. "1. id=171, PlayerName, pos=..."
-		parts := strings.Split(line, ",")
-
-		for i, part := range parts {
-			part = strings.TrimSpace(part)
-			kv := strings.SplitN(part, "=", 2)
+		p := model.Player{ID: head[1], Online: true}
+		rest := line[len(head[0]):]
 
-			if len(kv) == 2 {
-				keyRAW := strings.ToLower(strings.TrimSpace(kv[0]))
-				// Remove potential "1. " prefix "2. " etc.
-				// We trim digits, dots, and spaces from the LEFT only.
-				key := strings.TrimLeft(keyRAW, "0123456789. ")
+		// "1. id=33132, Grout" (lpi) has nothing after the name
+		if loc := rePlayerKeys.FindStringIndex(rest); loc != nil {
+			p.Name = strings.TrimSpace(rest[:loc[0]])
+			rest = rest[loc[0]+1:]
+		} else {
+			p.Name = strings.TrimSpace(rest)
+			rest = ""
+		}
 
-				val := strings.TrimSpace(kv[1])
+		stats := make(map[string]string)
+		for _, field := range splitFields(rest) {
+			if key, val, ok := splitKeyValue(field); ok {
 				stats[key] = val
-
-				// Direct mapping for known IDs if needed, but map approach is safer
-			} else if i == 1 {
-				// The second field is usually the name if it has no "="
-				// e.g. "1. id=..., Name Is Here, pos=..."
-				// But sometimes it might be stuck to the previous parts if we split efficiently.
-				// However, splitting by comma is usually safe for 7DTD unless name has comma.
-				p.Name = part
 			}
 		}
 
@@ -97,17 +85,9 @@ func ParsePlayers(output string) ([]model.Player, error) {
 			return 0
 		}
 
-		p.ID = stats["id"]
-		// If name wasn't set by position (2nd field), try to see if it was extracted?
-		// Actually, standard LPI output: "1. id=171, Name, pos=..."
-		// stats["id"] will be "171" (or "171" from "1. id=171"?)
-		// The first chunk is often "1. id=171".
-		// Let's clean the ID lookup.
-		if p.ID == "" {
-			// Try to handle "1. id=171" case for first split
-			// This logic happens in the loop above.
-		}
-
+		p.Position, _ = ParseVec3(stats["pos"])
+		p.Rotation, _ = ParseVec3(stats["rot"])
+		p.Remote = parseBool(stats["remote"])
 		p.Level = getInt("level")
 		p.Health = getInt("health")
 		p.Deaths = getInt("deaths")
@@ -115,118 +95,125 @@ func ParsePlayers(output string) ([]model.Player, error) {
 		p.PlayerKills = getInt("players")
 		p.Score = getInt("score")
 		p.Ping = getInt("ping")
+		p.IP = stats["ip"]
+		p.CrossplatformID = stats["crossid"]
+
+		// 'lp' uses pltfmid=Steam_7656..., older versions print steamid=7656...
+		p.PlatformID = stats["pltfmid"]
 		p.SteamID = stats["steamid"]
+		if p.PlatformID == "" && p.SteamID != "" {
+			p.PlatformID = "Steam_" + p.SteamID
+		}
 		if p.SteamID == "" {
-			// Fallback for 'lp' command which uses pltfmid
-			// e.g. pltfmid=Steam_7656...
-			if val, ok := stats["pltfmid"]; ok {
-				p.SteamID = val
-			}
+			p.SteamID = p.PlatformID
 		}
-		p.IP = stats["ip"]
 
-		// Fallback for Name if not found in 2nd position (unlikely but safe)
+		// Filter out simple observers (Telnet connections)
+		// Observers (like this tool) usually show up as "id=1" with no name.
+		// Real players should have a name.
 		if p.Name == "" {
-			if n, ok := stats["name"]; ok {
-				p.Name = n
-			}
-		}
-
-		if p.ID != "" {
-			// Filter out simple observers (Telnet connections)
-			// Observers (like this tool) usually show up as "id=1" with no name.
-			// Real players should have a name.
-			if p.Name == "" {
-				continue
-			}
-			players = append(players, p)
+			continue
 		}
+		players = append(players, p)
 	}
 	return players, nil
 }
 
-func ParseMem(output string) (heapUsed string, heapMax string, fps string) {
-	// Don't strict sanitize here because ParseMem relies on specific lines,
-	// but sanitizing log lines is good.
-	// However, the output "Time: 32.55m FPS: 14.07 Heap: ..." is one line.
-	// The sanitizer won't hurt it.
-	output = sanitizeOutput(output)
-
-	// Example: Heap: 2500.5 MB, Max: 3500.0 MB, ... FPS: 58.4
-	// Revert single-line replacement strategy because sanitize handles newlines
-	// But let's keep it safe.
-	output = strings.ReplaceAll(output, "\n", " ")
-
-	// Robust extract (regex is safer for these mixed strings)
-	reHeap := regexp.MustCompile(`Heap:\s*([\d\.]+\s*MB)`)
-	reMax := regexp.MustCompile(`Max:\s*([\d\.]+\s*MB)`)
-	reFps := regexp.MustCompile(`FPS:\s*([\d\.]+)`)
+var reMemField = regexp.MustCompile(`(\w+):\s*([\d.]+)(?:\s?(KB|MB|GB|[smhd])\b)?(?:\s*\((\d+)\))?`)
 
-	if m := reHeap.FindStringSubmatch(output); len(m) > 1 {
-		heapUsed = m[1]
-	}
-	if m := reMax.FindStringSubmatch(output); len(m) > 1 {
-		heapMax = m[1]
-	}
-	if m := reFps.FindStringSubmatch(output); len(m) > 1 {
-		fps = m[1]
-	}
-
-	return
-}
-
-func ParseEntities(output string) (zombies, animals, other int) {
+// ParseMem parses the single stats line printed by "mem". Older servers print
+// fewer fields; missing ones stay zero.
+func ParseMem(output string) (model.MemStats, error) {
 	output = sanitizeOutput(output)
-	// Simple line scan
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		lower := strings.ToLower(line)
+	// Keep it safe if the line was wrapped
+	output = strings.ReplaceAll(output, "\n", " ")
 
-		// 7DTD types are usually "EntityZombie", "EntityPlayer", "EntityAnimal"
-		// But in old versions or logs they might differ.
-		// Check for Zombies
-		if strings.Contains(lower, "zombie") && strings.Contains(lower, "type=") {
-			zombies++
+	var mem model.MemStats
+	found := false
+	for _, m := range reMemField.FindAllStringSubmatch(output, -1) {
+		key, unit := m[1], m[3]
+		num, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
 			continue
 		}
-
-		// Check for Animals (Specific types often don't say "Animal")
-		// "type=EntityStag", "type=EntityBoar", "EntityRabbit", "EntityWolf", "EntityBear"
-		isAnimal := false
-		animalKeywords := []string{
-			"animal", "stag", "boar", "rabbit", "wolf", "bear",
-			"chicken", "snake", "vulture", "coyote", "pig",
-		}
-		for _, kw := range animalKeywords {
-			if strings.Contains(lower, kw) && strings.Contains(lower, "type=") {
-				isAnimal = true
-				break
+		count := int(num)
+
+		switch key {
+		case "Time":
+			mem.Uptime = toDuration(num, unit)
+		case "FPS":
+			mem.FPS = num
+			found = true
+		case "Heap":
+			mem.HeapMB = toMB(num, unit)
+		case "Max":
+			mem.MaxMB = toMB(num, unit)
+		case "Chunks":
+			mem.Chunks = count
+		case "CGO":
+			mem.CGO = count
+		case "Ply":
+			mem.Players = count
+		case "Zom":
+			mem.Zombies = count
+		case "Ent":
+			mem.Entities = count
+			if m[4] != "" {
+				mem.EntitiesTotal, _ = strconv.Atoi(m[4])
 			}
+		case "Items":
+			mem.Items = count
+		case "CO":
+			mem.CO = count
+		case "RSS":
+			mem.RSSMB = toMB(num, unit)
 		}
-		if isAnimal {
-			animals++
-			continue
-		}
+	}
 
-		// Ignore Players (counted elsewhere)
-		if strings.Contains(lower, "player") && strings.Contains(lower, "type=") {
-			continue
-		}
+	if !found {
+		return mem, fmt.Errorf("no FPS in mem output")
+	}
+	return mem, nil
+}
 
-		// Count everything else as "Other" (Backpacks, Items, SupplyCrates)
-		if strings.Contains(lower, "id=") {
-			other++
-		}
+// toMB converts a size with a unit suffix ("MB", "GB", "KB") to megabytes
+func toMB(v float64, unit string) float64 {
+	switch strings.ToUpper(unit) {
+	case "KB":
+		return v / 1024
+	case "GB":
+		return v * 1024
+	default:
+		return v
+	}
+}
+
+// toDuration converts "32.55m" style values; plain numbers are seconds
+func toDuration(v float64, unit string) time.Duration {
+	scale := time.Second
+	switch unit {
+	case "m":
+		scale = time.Minute
+	case "h":
+		scale = time.Hour
+	case "d":
+		scale = 24 * time.Hour
 	}
-	return
+	// Rounded, 32.55 minutes would be a nanosecond short otherwise
+	return time.Duration(math.Round(v * float64(scale)))
 }
 
-func ParseTime(output string) string {
+var reGameTime = regexp.MustCompile(`Day (\d+), (\d{1,2}):(\d{2})`)
+
+// ParseTime parses "gettime" output, e.g. "Day 7, 21:45"
+func ParseTime(output string) (model.GameTime, error) {
 	output = sanitizeOutput(output)
-	// Example: Day 7, 21:45
-	lines := strings.Split(strings.TrimSpace(output), "\n")
-	if len(lines) > 0 {
-		return lines[0] // usually just one line response for gettime after sanitize
+	m := reGameTime.FindStringSubmatch(output)
+	if m == nil {
+		return model.GameTime{}, fmt.Errorf("no game time in %q", strings.TrimSpace(output))
 	}
-	return ""
+	day, _ := strconv.Atoi(m[1])
+	hour, _ := strconv.Atoi(m[2])
+	minute, _ := strconv.Atoi(m[3])
+	return model.GameTime{Day: day, Hour: hour, Minute: minute}, nil
 }
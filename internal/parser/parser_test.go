@@ -0,0 +1,202 @@
+package parser
+
+import (
+	"7dtd-monitor/internal/model"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Replies as captured in debug_output.txt, echo line included
+const (
+	lpiOutput = `2025-12-10T10:35:09 1991.928 INF Executing command 'lpi' by Telnet from 10.0.2.12:38754
+1. id=33132, Grout
+Total of 1 in the game`
+
+	leOutput = `2025-12-10T10:35:09 1991.932 INF Executing command 'le' by Telnet from 10.0.2.12:38754
+1. id=33134, [type=EntityAnimalRabbit, name=animalChicken, id=33134], pos=(42.4, 37.1, 1238.4), rot=(0.0, 41.3, 0.0), lifetime=float.Max, remote=False, dead=False, health=10
+2. id=33133, [type=EntityAnimalRabbit, name=animalRabbit, id=33133], pos=(-23.5, 37.7, 1202.2), rot=(0.0, 359.6, 0.0), lifetime=float.Max, remote=False, dead=False, health=8
+3. id=33132, [type=EntityPlayer, name=Grout, id=33132], pos=(14.3, 37.2, 1240.8), rot=(-16.9, 206.7, 0.0), lifetime=float.Max, remote=True, dead=False, health=100
+Total of 3 in the game`
+
+	memOutput = `2025-12-10T10:35:09 1991.370 INF Executing command 'mem' by Telnet from 10.0.2.12:38754
+Time: 32.55m FPS: 14.07 Heap: 1918.7MB Max: 1918.7MB Chunks: 249 CGO: 23 Ply: 1 Zom: 0 Ent: 3 (3) Items: 0 CO: 1 RSS: 2924.3MB`
+)
+
+func TestParsePlayers(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []model.Player
+	}{
+		{
+			name:   "lpi",
+			output: lpiOutput,
+			want:   []model.Player{{ID: "33132", Name: "Grout", Online: true}},
+		},
+		{
+			name: "lp with a comma in the name",
+			output: "1. id=171, Survivor, the (PL), pos=(-1050.5, 65.0, 890.3), rot=(0.0, -135.0, 0.0), remote=True, health=150, deaths=2, zombies=0, players=0, score=15, level=13, " +
+				"pltfmid=Steam_76561198012345678, crossid=EOS_0002aaaaaaaa, ip=127.0.0.1, ping=24",
+			want: []model.Player{{
+				ID: "171", Name: "Survivor, the (PL)", Level: 13, Health: 150, Deaths: 2, Score: 15, Ping: 24,
+				SteamID: "Steam_76561198012345678", PlatformID: "Steam_76561198012345678", CrossplatformID: "EOS_0002aaaaaaaa",
+				IP: "127.0.0.1", Position: model.Vec3{X: -1050.5, Y: 65, Z: 890.3}, Rotation: model.Vec3{Y: -135},
+				Remote: true, Online: true,
+			}},
+		},
+		{
+			name:   "older server with steamid",
+			output: "1. id=171, Grout, pos=(1.0, 2.0, 3.0), rot=(0.0, 0.0, 0.0), remote=True, health=100, deaths=0, zombies=7, players=1, score=3, level=2, steamid=76561198012345678, ip=10.0.0.5, ping=30",
+			want: []model.Player{{
+				ID: "171", Name: "Grout", Level: 2, Health: 100, Zombies: 7, PlayerKills: 1, Score: 3, Ping: 30,
+				SteamID: "76561198012345678", PlatformID: "Steam_76561198012345678", IP: "10.0.0.5",
+				Position: model.Vec3{X: 1, Y: 2, Z: 3}, Remote: true, Online: true,
+			}},
+		},
+		{
+			// Printed after the mem stats, the telnet session itself
+			name:   "observer",
+			output: "Observers\n id=1",
+		},
+		{
+			name:   "nobody online",
+			output: "Total of 0 in the game",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePlayers(tt.output)
+			if err != nil {
+				t.Fatalf("ParsePlayers: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePlayers =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEntities(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []model.Entity
+	}{
+		{
+			name:   "le",
+			output: leOutput,
+			want: []model.Entity{
+				{
+					ID: "33134", Class: "EntityAnimalRabbit", Name: "animalChicken", Category: model.CategoryAnimal,
+					Position: model.Vec3{X: 42.4, Y: 37.1, Z: 1238.4}, Rotation: model.Vec3{Y: 41.3},
+					Lifetime: math.MaxFloat32, Health: 10,
+				},
+				{
+					ID: "33133", Class: "EntityAnimalRabbit", Name: "animalRabbit", Category: model.CategoryAnimal,
+					Position: model.Vec3{X: -23.5, Y: 37.7, Z: 1202.2}, Rotation: model.Vec3{Y: 359.6},
+					Lifetime: math.MaxFloat32, Health: 8,
+				},
+				{
+					ID: "33132", Class: "EntityPlayer", Name: "Grout", Category: model.CategoryPlayer,
+					Position: model.Vec3{X: 14.3, Y: 37.2, Z: 1240.8}, Rotation: model.Vec3{X: -16.9, Y: 206.7},
+					Lifetime: math.MaxFloat32, Remote: true, Health: 100,
+				},
+			},
+		},
+		{
+			name:   "dead zombie with a lifetime",
+			output: "1. id=171, [type=EntityZombie, name=zombieBoe, id=171], pos=(1.5, 2.0, -3.5), rot=(0.0, 90.0, 0.0), lifetime=12.5, remote=False, dead=True, health=0",
+			want: []model.Entity{{
+				ID: "171", Class: "EntityZombie", Name: "zombieBoe", Category: model.CategoryZombie,
+				Position: model.Vec3{X: 1.5, Y: 2, Z: -3.5}, Rotation: model.Vec3{Y: 90},
+				Lifetime: 12.5, Dead: true,
+			}},
+		},
+		{
+			name:   "player with a comma in the name",
+			output: "1. id=171, [type=EntityPlayer, name=Survivor, the (PL), id=171], pos=(-1050.5, 65.0, 890.3), rot=(0.0, -135.0, 0.0), lifetime=float.Max, remote=True, dead=False, health=150",
+			want: []model.Entity{{
+				ID: "171", Class: "EntityPlayer", Name: "Survivor, the (PL)", Category: model.CategoryPlayer,
+				Position: model.Vec3{X: -1050.5, Y: 65, Z: 890.3}, Rotation: model.Vec3{Y: -135},
+				Lifetime: math.MaxFloat32, Remote: true, Health: 150,
+			}},
+		},
+		{
+			// lpi lines have an id but no class
+			name:   "player list",
+			output: lpiOutput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEntities(tt.output)
+			if err != nil {
+				t.Fatalf("ParseEntities: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseEntities =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMem(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    model.MemStats
+		wantErr bool
+	}{
+		{
+			name:   "mem",
+			output: memOutput,
+			want: model.MemStats{
+				Uptime: 1953 * time.Second, FPS: 14.07, HeapMB: 1918.7, MaxMB: 1918.7,
+				Chunks: 249, CGO: 23, Players: 1, Entities: 3, EntitiesTotal: 3, CO: 1, RSSMB: 2924.3,
+			},
+		},
+		{
+			name:   "wrapped, followed by observers",
+			output: "Time: 1.5h FPS: 60.00 Heap: 512.0MB Max: 1.5GB Chunks: 10\nCGO: 2 Ply: 0 Zom: 4 Ent: 6 (9) Items: 1 CO: 0 RSS: 2048KB\nObservers\n id=1",
+			want: model.MemStats{
+				Uptime: 90 * time.Minute, FPS: 60, HeapMB: 512, MaxMB: 1536, Chunks: 10,
+				CGO: 2, Zombies: 4, Entities: 6, EntitiesTotal: 9, Items: 1, RSSMB: 2,
+			},
+		},
+		{
+			name:   "older server",
+			output: "Time: 12.00m FPS: 35.20 Heap: 800.5MB Max: 1024.0MB Chunks: 100 CGO: 5 Ply: 2 Zom: 8 Ent: 20 (25) Items: 3",
+			want: model.MemStats{
+				Uptime: 12 * time.Minute, FPS: 35.2, HeapMB: 800.5, MaxMB: 1024, Chunks: 100,
+				CGO: 5, Players: 2, Zombies: 8, Entities: 20, EntitiesTotal: 25, Items: 3,
+			},
+		},
+		{
+			name:    "no stats",
+			output:  "2025-12-10T10:35:09 1991.370 INF Executing command 'mem' by Telnet from 10.0.2.12:38754",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMem(tt.output)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseMem = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMem: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMem =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
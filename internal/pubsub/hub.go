@@ -0,0 +1,51 @@
+// Package pubsub fans values out to any number of subscribers without
+// letting a slow one hold up the publisher.
+package pubsub
+
+import "sync"
+
+// Values buffered per subscriber before new ones are dropped
+const bufferSize = 256
+
+// Hub delivers published values to every subscriber. The zero value is ready
+// to use. A Hub must not be copied after first use.
+type Hub[T any] struct {
+	mu   sync.Mutex
+	subs map[chan T]struct{}
+}
+
+// Subscribe returns a channel receiving values and a function to stop the
+// subscription, which closes the channel. Slow subscribers miss values
+// rather than stalling Publish.
+func (h *Hub[T]) Subscribe() (<-chan T, func()) {
+	ch := make(chan T, bufferSize)
+
+	h.mu.Lock()
+	if h.subs == nil {
+		h.subs = make(map[chan T]struct{})
+	}
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	cancel := func() {
+		h.mu.Lock()
+		if _, ok := h.subs[ch]; ok {
+			delete(h.subs, ch)
+			close(ch)
+		}
+		h.mu.Unlock()
+	}
+	return ch, cancel
+}
+
+// Publish sends v to every subscriber with room for it, it never blocks
+func (h *Hub[T]) Publish(v T) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- v:
+		default:
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package pubsub
+
+import "testing"
+
+func TestPublishReachesEverySubscriber(t *testing.T) {
+	var h Hub[int]
+	a, cancelA := h.Subscribe()
+	defer cancelA()
+	b, cancelB := h.Subscribe()
+	defer cancelB()
+
+	h.Publish(42)
+	for _, ch := range []<-chan int{a, b} {
+		if got := <-ch; got != 42 {
+			t.Errorf("got %d, want 42", got)
+		}
+	}
+}
+
+func TestCancelClosesOnce(t *testing.T) {
+	var h Hub[int]
+	ch, cancel := h.Subscribe()
+	cancel()
+	cancel()
+
+	if _, ok := <-ch; ok {
+		t.Error("channel still open after cancel")
+	}
+	// Nobody is left to receive it
+	h.Publish(1)
+}
+
+func TestSlowSubscriberMissesValues(t *testing.T) {
+	var h Hub[int]
+	ch, cancel := h.Subscribe()
+	defer cancel()
+
+	for i := range bufferSize + 10 {
+		h.Publish(i)
+	}
+	if len(ch) != bufferSize {
+		t.Fatalf("%d values buffered, want %d", len(ch), bufferSize)
+	}
+	if got := <-ch; got != 0 {
+		t.Errorf("first value %d, want 0", got)
+	}
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"7dtd-monitor/internal/events"
+	"7dtd-monitor/internal/model"
+	"sync"
+	"time"
+)
+
+const (
+	maxPings  = 60  // Two minutes of stats polls
+	maxEvents = 100 // Per player
+)
+
+// PlayerHistory is what happened to one player since the monitor started
+type PlayerHistory struct {
+	SessionStart time.Time      // Zero while offline
+	Pings        []int          // Oldest first
+	Events       []events.Event // Oldest first: connects, chat, deaths, kills, kicks, bans
+}
+
+// History keeps recent per-player activity. Unlike KnownPlayers it lives in
+// memory only, the server has no way to give it back after a restart.
+type History struct {
+	mu      sync.Mutex
+	players map[string]*PlayerHistory // By platform ID
+	byName  map[string]string         // Platform ID by name, for name-only events
+}
+
+func NewHistory() *History {
+	return &History{
+		players: make(map[string]*PlayerHistory),
+		byName:  make(map[string]string),
+	}
+}
+
+func (h *History) get(platformID string) *PlayerHistory {
+	ph, ok := h.players[platformID]
+	if !ok {
+		ph = &PlayerHistory{}
+		h.players[platformID] = ph
+	}
+	return ph
+}
+
+// UpdatePlayers records a poll of the online players
+func (h *History) UpdatePlayers(players []model.Player, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	online := make(map[string]bool, len(players))
+	for _, p := range players {
+		if p.PlatformID == "" {
+			continue
+		}
+		online[p.PlatformID] = true
+		h.byName[p.Name] = p.PlatformID
+
+		ph := h.get(p.PlatformID)
+		if ph.SessionStart.IsZero() {
+			ph.SessionStart = now
+		}
+		ph.Pings = append(ph.Pings, p.Ping)
+		if len(ph.Pings) > maxPings {
+			ph.Pings = ph.Pings[len(ph.Pings)-maxPings:]
+		}
+	}
+
+	// Missed disconnect events
+	for id, ph := range h.players {
+		if !online[id] {
+			ph.SessionStart = time.Time{}
+		}
+	}
+}
+
+// Record adds a game event to the history of every player it involves
+func (h *History) Record(ev events.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	add := func(p events.Player) {
+		id := p.PlatformID
+		if id == "" {
+			id = h.byName[p.Name]
+		}
+		if id == "" {
+			return
+		}
+		if p.Name != "" {
+			h.byName[p.Name] = id
+		}
+
+		ph := h.get(id)
+		switch ev.Type {
+		case events.PlayerConnected:
+			ph.SessionStart = ev.Time
+		case events.PlayerDisconnected:
+			ph.SessionStart = time.Time{}
+		}
+		ph.Events = append(ph.Events, ev)
+		if len(ph.Events) > maxEvents {
+			ph.Events = ph.Events[len(ph.Events)-maxEvents:]
+		}
+	}
+
+	switch ev.Type {
+	case events.PlayerConnected, events.PlayerDisconnected, events.PlayerDied,
+		events.ChatMessage, events.Kick, events.Ban:
+		add(ev.Player)
+	case events.PlayerKilledByPlayer:
+		add(ev.Player)
+		add(ev.Killer)
+	}
+}
+
+// Get returns a copy of one player's history
+func (h *History) Get(platformID string) PlayerHistory {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ph, ok := h.players[platformID]
+	if !ok {
+		return PlayerHistory{}
+	}
+	return PlayerHistory{
+		SessionStart: ph.SessionStart,
+		Pings:        append([]int(nil), ph.Pings...),
+		Events:       append([]events.Event(nil), ph.Events...),
+	}
+}
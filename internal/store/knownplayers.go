@@ -0,0 +1,161 @@
+// Package store keeps what the monitor knows about players beyond a single
+// poll: the persistent known players database and in-memory history.
+package store
+
+import (
+	"7dtd-monitor/internal/model"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KnownPlayers is a persistent database of every player seen on the server,
+// keyed by platform ID. It is fed from "lkp" output and saved as JSON.
+type KnownPlayers struct {
+	path string
+
+	mu      sync.Mutex
+	players map[string]*model.KnownPlayer
+}
+
+// OpenKnownPlayers loads the database at path. A missing file is not an error.
+func OpenKnownPlayers(path string) (*KnownPlayers, error) {
+	k := &KnownPlayers{
+		path:    path,
+		players: make(map[string]*model.KnownPlayer),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return k, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []model.KnownPlayer
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	for i := range list {
+		k.players[list[i].PlatformID] = &list[i]
+	}
+	return k, nil
+}
+
+// Update merges freshly parsed players into the database
+func (k *KnownPlayers) Update(list []model.KnownPlayer) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now()
+	for _, p := range list {
+		known, ok := k.players[p.PlatformID]
+		if !ok {
+			p.FirstSeen = now
+			if p.IP != "" {
+				p.IPs = []string{p.IP}
+			}
+			k.players[p.PlatformID] = &p
+			continue
+		}
+
+		known.Name = p.Name
+		known.EntityID = p.EntityID
+		known.CrossplatformID = p.CrossplatformID
+		known.Online = p.Online
+		known.Playtime = p.Playtime
+		if p.LastOnline.After(known.LastOnline) {
+			known.LastOnline = p.LastOnline
+		}
+		if p.IP != "" && !slices.Contains(known.IPs, p.IP) {
+			known.IPs = append(known.IPs, p.IP)
+		}
+		if p.IP != "" {
+			known.IP = p.IP
+		}
+	}
+}
+
+// Get looks a player up by platform ID
+func (k *KnownPlayers) Get(platformID string) (model.KnownPlayer, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	p, ok := k.players[platformID]
+	if !ok {
+		return model.KnownPlayer{}, false
+	}
+	return *p, true
+}
+
+// Search returns players whose name, platform ID or IP contains query
+// (case insensitive), most recently online first. An empty query returns everyone.
+func (k *KnownPlayers) Search(query string) []model.KnownPlayer {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	var result []model.KnownPlayer
+	for _, p := range k.players {
+		if query == "" || matches(p, query) {
+			result = append(result, *p)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Online != result[j].Online {
+			return result[i].Online
+		}
+		return result[i].LastOnline.After(result[j].LastOnline)
+	})
+	return result
+}
+
+func matches(p *model.KnownPlayer, query string) bool {
+	if strings.Contains(strings.ToLower(p.Name), query) ||
+		strings.Contains(strings.ToLower(p.PlatformID), query) {
+		return true
+	}
+	for _, ip := range p.IPs {
+		if strings.Contains(ip, query) {
+			return true
+		}
+	}
+	return false
+}
+
+// Save writes the database, replacing the file atomically
+func (k *KnownPlayers) Save() error {
+	k.mu.Lock()
+	list := make([]model.KnownPlayer, 0, len(k.players))
+	for _, p := range k.players {
+		list = append(list, *p)
+	}
+	k.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].PlatformID < list[j].PlatformID })
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(k.path, data)
+}
+
+// writeFile writes via a temp file so a crash never leaves a half written file
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"7dtd-monitor/internal/model"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUpdateKeepsDistinctIPs(t *testing.T) {
+	k, err := OpenKnownPlayers(filepath.Join(t.TempDir(), "known_players.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ip := range []string{"10.0.0.5", "192.168.0.5", "", "10.0.0.5", "192.168.0.5"} {
+		k.Update([]model.KnownPlayer{{Name: "Grout", PlatformID: "Steam_76561198012345678", IP: ip}})
+	}
+
+	p, _ := k.Get("Steam_76561198012345678")
+	if want := []string{"10.0.0.5", "192.168.0.5"}; !reflect.DeepEqual(p.IPs, want) {
+		t.Errorf("IPs = %q, want %q", p.IPs, want)
+	}
+	if p.IP != "192.168.0.5" {
+		t.Errorf("IP = %q, want the latest", p.IP)
+	}
+}
+
+func TestSaveAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_players.json")
+	k, err := OpenKnownPlayers(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.Update([]model.KnownPlayer{{Name: "Grout", PlatformID: "Steam_76561198012345678", IP: "10.0.0.5", Playtime: 1834 * 60}})
+	if err := k.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"platform_id": "Steam_76561198012345678"`, `"playtime": 110040`, `"ips": [`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("saved file lacks %s:\n%s", want, data)
+		}
+	}
+
+	reopened, err := OpenKnownPlayers(path)
+	if err != nil {
+		t.Fatalf("OpenKnownPlayers: %v", err)
+	}
+	got, _ := reopened.Get("Steam_76561198012345678")
+	want, _ := k.Get("Steam_76561198012345678")
+	if !got.FirstSeen.Equal(want.FirstSeen) {
+		t.Errorf("FirstSeen = %v, want %v", got.FirstSeen, want.FirstSeen)
+	}
+	got.FirstSeen = want.FirstSeen
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reopened %+v, want %+v", got, want)
+	}
+}
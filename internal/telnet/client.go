@@ -1,20 +1,68 @@
 package telnet
 
 import (
+	"7dtd-monitor/internal/pubsub"
 	"bufio"
+	"errors"
 	"fmt"
 	"net"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
+var (
+	ErrNotConnected = errors.New("not connected")
+	ErrClosed       = errors.New("client closed")
+	ErrAuthFailed   = errors.New("authentication failed: password incorrect")
+)
+
+// 7DTD echoes every command it runs into the log, e.g.
+// "2025-12-10T10:35:08 1991.171 INF Executing command 'gettime' by Telnet from 10.0.2.12:38754"
+// We use that line to find where the reply to our command starts.
+var reEcho = regexp.MustCompile(`INF Executing command '(.*)' by Telnet from`)
+
+const (
+	// Upper bound for the whole password exchange
+	authTimeout = 10 * time.Second
+	// How long we wait for the server to echo our command back
+	echoTimeout = 5 * time.Second
+	// How long a command with a known terminator may take to complete
+	replyTimeout = 10 * time.Second
+	// Inactivity period that marks the end of a reply without a terminator
+	quietPeriod = 250 * time.Millisecond
+)
+
 type Client struct {
 	Host     string
 	Port     string
 	Password string
-	conn     net.Conn
-	reader   *bufio.Reader
-	writer   *bufio.Writer
+
+	// Guards the fields below, which change on every reconnect
+	mu      sync.Mutex
+	conn    net.Conn
+	writer  *bufio.Writer
+	state   State
+	retryAt time.Time
+
+	// Commands are queued and executed one at a time by run()
+	queue     chan *request
+	done      chan struct{}
+	closeOnce sync.Once
+
+	subs pubsub.Hub[Event]
+}
+
+// request is a single queued command waiting for its reply
+type request struct {
+	cmd   string
+	reply chan result
+}
+
+type result struct {
+	output string
+	err    error
 }
 
 func NewClient(host, port, password string) *Client {
@@ -22,110 +70,321 @@ func NewClient(host, port, password string) *Client {
 		Host:     host,
 		Port:     port,
 		Password: password,
+		queue:    make(chan *request),
+		done:     make(chan struct{}),
 	}
 }
 
+// Connect makes the first connection attempt and, if it succeeds, keeps the
+// client connected in the background, reconnecting with backoff when the
+// server goes away.
 func (c *Client) Connect() error {
+	c.setState(StateConnecting, time.Time{}, nil)
+	lines, err := c.dial()
+	if err != nil {
+		c.setState(failedState(err), time.Time{}, err)
+		return err
+	}
+	c.setState(StateAuthenticated, time.Time{}, nil)
+
+	go c.supervise(lines)
+	return nil
+}
+
+// dial opens a new connection, authenticates and starts its line reader.
+func (c *Client) dial() (<-chan line, error) {
 	address := net.JoinHostPort(c.Host, c.Port)
 	conn, err := net.DialTimeout("tcp", address, 5*time.Second)
 	if err != nil {
-		return err
+		return nil, err
 	}
+
+	c.mu.Lock()
 	c.conn = conn
-	c.reader = bufio.NewReader(conn)
 	c.writer = bufio.NewWriter(conn)
+	c.mu.Unlock()
 
 	// Authenticate
-	return c.authenticate()
+	if err := c.authenticate(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	lines := make(chan line, 256)
+	go readLines(conn, lines)
+	return lines, nil
+}
+
+// KeepConnecting is Connect for daemons that may start before the server. It
+// returns the first attempt's error like Connect, but unless the password was
+// rejected the client keeps retrying in the background with the same backoff
+// as after a lost connection.
+func (c *Client) KeepConnecting() error {
+	err := c.Connect()
+	if err == nil || errors.Is(err, ErrAuthFailed) {
+		return err
+	}
+	go func() {
+		if lines := c.reconnect(err); lines != nil {
+			c.supervise(lines)
+		}
+	}()
+	return err
+}
+
+// supervise serves commands while connected and reconnects when the
+// connection is lost, until the client is closed.
+func (c *Client) supervise(lines <-chan line) {
+	for {
+		err := c.run(lines)
+		if errors.Is(err, ErrClosed) {
+			return
+		}
+		if lines = c.reconnect(err); lines == nil {
+			return
+		}
+	}
+}
+
+// reconnect dials with backoff until it gets through. It returns nil once
+// the client is closed or the password is rejected.
+func (c *Client) reconnect(err error) <-chan line {
+	delay := minBackoff
+	for {
+		retryAt := time.Now().Add(delay)
+		c.setState(StateDisconnected, retryAt, err)
+		if !c.wait(retryAt) {
+			return nil
+		}
+
+		c.setState(StateConnecting, time.Time{}, nil)
+		var lines <-chan line
+		lines, err = c.dial()
+		if err == nil {
+			c.setState(StateAuthenticated, time.Time{}, nil)
+			return lines
+		}
+		if errors.Is(err, ErrAuthFailed) {
+			// Retrying with the same password won't help
+			c.setState(StateAuthFailed, time.Time{}, err)
+			c.wait(time.Time{})
+			return nil
+		}
+		delay = nextBackoff(delay)
+	}
+}
+
+// wait sleeps until t, failing any commands queued meanwhile. A zero t
+// waits until the client is closed. It returns false if the client was closed.
+func (c *Client) wait(t time.Time) bool {
+	var expired <-chan time.Time
+	if !t.IsZero() {
+		timer := time.NewTimer(time.Until(t))
+		defer timer.Stop()
+		expired = timer.C
+	}
+	for {
+		select {
+		case req := <-c.queue:
+			req.reply <- result{err: ErrNotConnected}
+		case <-expired:
+			return true
+		case <-c.done:
+			return false
+		}
+	}
 }
 
+// authenticate performs the password exchange. A wrong password is reported
+// as ErrAuthFailed; a server that never answers fails after authTimeout.
 func (c *Client) authenticate() error {
+	c.mu.Lock()
+	conn, writer := c.conn, c.writer
+	c.mu.Unlock()
+
+	conn.SetDeadline(time.Now().Add(authTimeout))
+	defer conn.SetDeadline(time.Time{})
+
 	// Read initial prompt "Please enter password:"
-	c.readUntil("password:")
+	if _, err := readUntil(conn, "password:"); err != nil {
+		return fmt.Errorf("waiting for password prompt: %w", err)
+	}
 
 	// Send password
-	_, err := c.writer.WriteString(c.Password + "\r\n")
+	_, err := writer.WriteString(c.Password + "\r\n")
 	if err != nil {
 		return err
 	}
-	c.writer.Flush()
+	if err := writer.Flush(); err != nil {
+		return err
+	}
 
-	// Wait for success
-	_, err = c.readUntil("Logon successful")
-	return err
+	// Wait for success, the server re-prompts on a wrong password:
+	// "Password incorrect, please enter password:"
+	output, err := readUntil(conn, "Logon successful", "Password incorrect")
+	if err != nil {
+		return fmt.Errorf("waiting for logon: %w", err)
+	}
+	if strings.Contains(output, "Password incorrect") {
+		return ErrAuthFailed
+	}
+	return nil
 }
 
+// SendCommand queues cmd and blocks until its reply has been read.
+// It is safe to call from multiple goroutines; commands never overlap on the wire.
 func (c *Client) SendCommand(cmd string) (string, error) {
-	if c.conn == nil {
-		return "", fmt.Errorf("not connected")
+	if state, _ := c.State(); state != StateAuthenticated {
+		return "", ErrNotConnected
 	}
 
-	_, err := c.writer.WriteString(cmd + "\r\n")
-	if err != nil {
-		return "", err
+	req := &request{cmd: cmd, reply: make(chan result, 1)}
+	select {
+	case c.queue <- req:
+	case <-c.done:
+		return "", ErrClosed
 	}
-	c.writer.Flush()
 
-	// Wait a bit for the server to process strings
-	// 7DTD can be slow.
-	time.Sleep(300 * time.Millisecond)
-
-	var output strings.Builder
-	buffer := make([]byte, 4096)
+	res := <-req.reply
+	return res.output, res.err
+}
 
-	// Read Loop
-	// We want to keep reading as long as there is data, or until a strict timeout.
-	// Since we don't have a specific EOF/Prompt, we rely on a short inactivity timeout between chunks.
+// run is the only goroutine that writes to the connection while it is up.
+// Between commands it forwards log lines to subscribers. It returns when the
+// connection is lost or the client is closed.
+func (c *Client) run(lines <-chan line) error {
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+	defer func() {
+		conn.Close()
+		// Let the reader run into the closed connection and exit
+		for range lines {
+		}
+	}()
 
-	// First read (blocking with timeout)
-	c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
-	n, err := c.conn.Read(buffer)
-	if err != nil {
-		// If timeout and no data, we might return empty (cmd had no output?)
-		// But usually we get at least the log line.
-		if strings.Contains(err.Error(), "timeout") {
-			return "", nil
+	for {
+		select {
+		case req := <-c.queue:
+			output, err := c.execute(req.cmd, lines)
+			req.reply <- result{output: output, err: err}
+			if errors.Is(err, ErrNotConnected) || errors.Is(err, ErrClosed) {
+				return err
+			}
+		case l, ok := <-lines:
+			if !ok {
+				return ErrNotConnected
+			}
+			c.forward(l)
+		case <-c.done:
+			return ErrClosed
 		}
-		return "", err
 	}
-	output.Write(buffer[:n])
+}
+
+// execute writes cmd and collects the output lines that follow the server's
+// echo of it. Log lines seen meanwhile are forwarded to subscribers.
+func (c *Client) execute(cmd string, lines <-chan line) (string, error) {
+	c.mu.Lock()
+	writer := c.writer
+	c.mu.Unlock()
+
+	writer.WriteString(cmd + "\r\n")
+	if err := writer.Flush(); err != nil {
+		return "", ErrNotConnected
+	}
 
-	// Subsequent reads: shorter timeout to drain buffer
+	term := terminatorFor(cmd)
+	timer := time.NewTimer(echoTimeout)
+	defer timer.Stop()
+
+	echoed := false
+	var output strings.Builder
 	for {
-		c.conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
-		n, err := c.conn.Read(buffer)
-		if n > 0 {
-			output.Write(buffer[:n])
-		}
-		if err != nil {
-			// Timeout means we are done reading for now
-			break
+		select {
+		case l, ok := <-lines:
+			if !ok {
+				return output.String(), ErrNotConnected
+			}
+			switch {
+			case !echoed:
+				if l.kind == lineEcho && l.cmd == cmd {
+					echoed = true
+					if term != nil {
+						timer.Reset(replyTimeout)
+					} else {
+						timer.Reset(quietPeriod)
+					}
+				} else {
+					// Leftovers from an earlier command or unsolicited logs
+					c.forward(l)
+				}
+			case l.kind == lineOutput:
+				output.WriteString(l.text)
+				output.WriteString("\n")
+				if term == nil {
+					timer.Reset(quietPeriod)
+				} else if term.MatchString(l.text) {
+					return output.String(), nil
+				}
+			default:
+				c.forward(l)
+			}
+		case <-timer.C:
+			if !echoed {
+				return "", fmt.Errorf("no reply to %q", cmd)
+			}
+			if term != nil {
+				return output.String(), fmt.Errorf("incomplete reply to %q", cmd)
+			}
+			return output.String(), nil
+		case <-c.done:
+			return output.String(), ErrClosed
 		}
 	}
-	// Reset deadline
-	c.conn.SetReadDeadline(time.Time{})
+}
 
-	return output.String(), nil
+// forward publishes log lines; stray output that belongs to no command is dropped.
+func (c *Client) forward(l line) {
+	if l.kind == lineOutput {
+		return
+	}
+	c.publish(Event{Type: EventLog, Line: l.text})
 }
 
-func (c *Client) readUntil(substring string) (string, error) {
+// readUntil reads until any of the substrings has been seen
+func readUntil(conn net.Conn, substrings ...string) (string, error) {
 	var output strings.Builder
 	buffer := make([]byte, 1024)
 	for {
-		n, err := c.conn.Read(buffer)
+		n, err := conn.Read(buffer)
 		if err != nil {
 			return output.String(), err
 		}
 		chunk := string(buffer[:n])
 		output.WriteString(chunk)
-		if strings.Contains(output.String(), substring) {
-			return output.String(), nil
+		for _, substring := range substrings {
+			if strings.Contains(output.String(), substring) {
+				return output.String(), nil
+			}
 		}
 	}
 }
 
 func (c *Client) Close() {
+	c.closeOnce.Do(func() { close(c.done) })
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if c.conn != nil {
 		c.conn.Close()
 	}
 }
+
+// failedState maps a dial error to the state it leaves the client in
+func failedState(err error) State {
+	if errors.Is(err, ErrAuthFailed) {
+		return StateAuthFailed
+	}
+	return StateDisconnected
+}
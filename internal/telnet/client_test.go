@@ -0,0 +1,456 @@
+package telnet
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Transcripts as test/mock_server.go sends them
+const (
+	lpReply = `1. id=171, Survivor, the (PL), pos=(-1050.5, 65.0, 890.3), rot=(0.0, -135.0, 0.0), remote=True, health=150, deaths=2, zombies=0, players=0, score=15, level=13, pltfmid=Steam_76561198012345678, crossid=EOS_0002aaaaaaaa, ip=127.0.0.1, ping=24
+2. id=172, ZombieSlayer, pos=(-1040.1, 65.0, 895.1), rot=(0.0, 45.0, 0.0), remote=True, health=80, deaths=5, zombies=12, players=1, score=55, level=24, pltfmid=Steam_76561198087654321, crossid=EOS_0002bbbbbbbb, ip=192.168.0.5, ping=45
+Total of 2 in the game
+`
+	ggsReply = `GameStat.BloodMoonDay = 7
+GameStat.DayLimitThisTurn = 0
+GameStat.LandClaimCount = 5
+GameStat.LandClaimSize = 41
+GameStat.ShowFriendPlayerOnMap = True
+`
+)
+
+// fakeServer speaks the 7DTD telnet protocol on a free local port. Each
+// command read after logon is passed to handle, which writes the reply.
+type fakeServer struct {
+	ln       net.Listener
+	password string
+	handle   func(c *fakeConn, cmd string)
+
+	// Connections that got past the password prompt
+	conns chan *fakeConn
+
+	mu   sync.Mutex
+	open []net.Conn
+}
+
+func newFakeServer(t *testing.T, password string, handle func(c *fakeConn, cmd string)) *fakeServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return startFakeServer(t, ln, password, handle)
+}
+
+func startFakeServer(t *testing.T, ln net.Listener, password string, handle func(c *fakeConn, cmd string)) *fakeServer {
+	s := &fakeServer{ln: ln, password: password, handle: handle, conns: make(chan *fakeConn, 10)}
+	t.Cleanup(s.close)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.open = append(s.open, conn)
+			s.mu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// close stops listening and drops every connection
+func (s *fakeServer) close() {
+	s.ln.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.open {
+		conn.Close()
+	}
+}
+
+// client returns a client for s, closed when the test ends
+func (s *fakeServer) client(t *testing.T, password string) *Client {
+	_, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	c := NewClient("127.0.0.1", port, password)
+	t.Cleanup(c.Close)
+	return c
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	c := &fakeConn{Conn: conn, w: bufio.NewWriter(conn)}
+	scanner := bufio.NewScanner(conn)
+
+	c.send("Please enter password:")
+	for {
+		if !scanner.Scan() {
+			return
+		}
+		if s.password == "" || scanner.Text() == s.password {
+			break
+		}
+		c.send("Password incorrect, please enter password:")
+	}
+	c.send("Logon successful.", "")
+	s.conns <- c
+
+	// Read ahead so the handler can tell when a command arrived
+	cmds := make(chan received, 10)
+	go func() {
+		defer close(cmds)
+		for scanner.Scan() {
+			cmds <- received{cmd: strings.TrimSpace(scanner.Text()), at: time.Now()}
+		}
+	}()
+	for r := range cmds {
+		c.mu.Lock()
+		c.received = append(c.received, r)
+		c.mu.Unlock()
+
+		s.handle(c, r.cmd)
+
+		c.mu.Lock()
+		c.replied = append(c.replied, time.Now())
+		c.mu.Unlock()
+	}
+}
+
+type received struct {
+	cmd string
+	at  time.Time
+}
+
+// fakeConn is the server side of one client connection
+type fakeConn struct {
+	net.Conn
+	w *bufio.Writer
+
+	mu       sync.Mutex
+	received []received
+	replied  []time.Time
+}
+
+// send writes lines the way the server terminates them
+func (c *fakeConn) send(lines ...string) {
+	for _, l := range lines {
+		c.w.WriteString(l + "\r\n")
+	}
+	c.w.Flush()
+}
+
+// log writes a server log line
+func (c *fakeConn) log(msg string) {
+	c.send(time.Now().Format("2006-01-02T15:04:05") + " 1991.171 INF " + msg)
+}
+
+// echo writes the log line the server prints before running cmd
+func (c *fakeConn) echo(cmd string) {
+	c.log(fmt.Sprintf("Executing command '%s' by Telnet from %s", cmd, c.RemoteAddr()))
+}
+
+// reply writes the echo and then the lines of output
+func (c *fakeConn) reply(cmd, output string) {
+	c.echo(cmd)
+	c.send(strings.Split(strings.TrimSuffix(output, "\n"), "\n")...)
+}
+
+func connect(t *testing.T, s *fakeServer) *Client {
+	t.Helper()
+	c := s.client(t, "")
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	return c
+}
+
+func TestCommandsDoNotOverlap(t *testing.T) {
+	s := newFakeServer(t, "", func(c *fakeConn, cmd string) {
+		c.echo(cmd)
+		// Reply in two bursts so a second command could sneak in between
+		c.send("reply to " + cmd)
+		time.Sleep(50 * time.Millisecond)
+		c.send("end of " + cmd)
+	})
+	client := connect(t, s)
+
+	const n = 3
+	outputs := make([]string, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, err := client.SendCommand(fmt.Sprintf("say %d", i))
+			if err != nil {
+				t.Errorf("say %d: %v", i, err)
+			}
+			outputs[i] = out
+		}()
+	}
+	wg.Wait()
+
+	for i, out := range outputs {
+		want := fmt.Sprintf("reply to say %d\nend of say %d\n", i, i)
+		if out != want {
+			t.Errorf("say %d = %q, want %q", i, out, want)
+		}
+	}
+
+	conn := <-s.conns
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	for i := 1; i < len(conn.received); i++ {
+		if conn.received[i].at.Before(conn.replied[i-1]) {
+			t.Errorf("%q was sent before %q was answered", conn.received[i].cmd, conn.received[i-1].cmd)
+		}
+	}
+}
+
+func TestReplyStartsAtEcho(t *testing.T) {
+	s := newFakeServer(t, "", func(c *fakeConn, cmd string) {
+		// Leftovers of an earlier command and an unrelated log line
+		c.send("Total of 3 in the game")
+		c.echo("lp")
+		c.log("GMSG: Player 'Newbie' died")
+
+		c.echo(cmd)
+		c.log("Chat (from 'Steam_76561198087654321', entity id '172', to 'Global'): 'ZombieSlayer': check the trader")
+		c.send("Day 7, 21:45")
+	})
+	client := connect(t, s)
+	events, cancel := client.Subscribe()
+	defer cancel()
+
+	out, err := client.SendCommand("gettime")
+	if err != nil {
+		t.Fatalf("SendCommand: %v", err)
+	}
+	if out != "Day 7, 21:45\n" {
+		t.Errorf("gettime = %q", out)
+	}
+
+	// Log lines are forwarded, stray output is not
+	want := []string{"Executing command 'lp'", "GMSG: Player 'Newbie' died", "'ZombieSlayer': check the trader"}
+	for _, w := range want {
+		ev := nextEvent(t, events, EventLog)
+		if !strings.Contains(ev.Line, w) {
+			t.Errorf("forwarded %q, want a line containing %q", ev.Line, w)
+		}
+	}
+}
+
+func TestReplyCompletion(t *testing.T) {
+	s := newFakeServer(t, "", func(c *fakeConn, cmd string) {
+		switch cmd {
+		case "lp":
+			c.reply(cmd, lpReply)
+			// Arrives within the quiet period, but after "Total of"
+			time.Sleep(quietPeriod / 2)
+			c.send("late output")
+		case "ggs":
+			lines := strings.Split(strings.TrimSuffix(ggsReply, "\n"), "\n")
+			c.reply(cmd, strings.Join(lines[:2], "\n"))
+			time.Sleep(quietPeriod / 2)
+			c.send(lines[2:]...)
+			// Arrives after the quiet period
+			time.Sleep(2 * quietPeriod)
+			c.send("late output")
+		default:
+			c.reply(cmd, "ok")
+		}
+	})
+	client := connect(t, s)
+
+	tests := []struct {
+		name string
+		cmd  string
+		want string
+	}{
+		{name: "terminator", cmd: "lp", want: lpReply},
+		{name: "quiet period", cmd: "ggs", want: ggsReply},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := client.SendCommand(tt.cmd)
+			if err != nil {
+				t.Fatalf("SendCommand: %v", err)
+			}
+			if out != tt.want {
+				t.Errorf("%s =\n%q\nwant\n%q", tt.cmd, out, tt.want)
+			}
+
+			// The late line must not end up in the next reply either
+			out, err = client.SendCommand("version")
+			if err != nil {
+				t.Fatalf("SendCommand: %v", err)
+			}
+			if out != "ok\n" {
+				t.Errorf("next command = %q, want %q", out, "ok\n")
+			}
+		})
+	}
+}
+
+// nextEvent returns the next event of type typ
+func nextEvent(t *testing.T, events <-chan Event, typ EventType) Event {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-events:
+			if ev.Type == typ {
+				return ev
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for an event")
+			return Event{}
+		}
+	}
+}
+
+func TestNextBackoff(t *testing.T) {
+	tests := []struct {
+		delay, want time.Duration
+	}{
+		{delay: minBackoff, want: 2 * time.Second},
+		{delay: 16 * time.Second, want: 32 * time.Second},
+		{delay: 32 * time.Second, want: maxBackoff},
+		{delay: maxBackoff, want: maxBackoff},
+	}
+	for _, tt := range tests {
+		if got := nextBackoff(tt.delay); got != tt.want {
+			t.Errorf("nextBackoff(%v) = %v, want %v", tt.delay, got, tt.want)
+		}
+	}
+}
+
+func TestReconnectAfterLostConnection(t *testing.T) {
+	s := newFakeServer(t, "", func(c *fakeConn, cmd string) { c.reply(cmd, "Day 7, 21:45") })
+	client := connect(t, s)
+	events, cancel := client.Subscribe()
+	defer cancel()
+
+	(<-s.conns).Close()
+	lost := time.Now()
+
+	ev := nextEvent(t, events, EventState)
+	if ev.State != StateDisconnected || ev.Err == nil {
+		t.Fatalf("after losing the connection got %v (%v), want Disconnected with an error", ev.State, ev.Err)
+	}
+	if wait := ev.RetryAt.Sub(lost); wait < minBackoff/2 || wait > 2*minBackoff {
+		t.Errorf("first retry in %v, want about %v", wait, minBackoff)
+	}
+	if _, err := client.SendCommand("gettime"); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("SendCommand while disconnected = %v, want ErrNotConnected", err)
+	}
+
+	if ev = nextEvent(t, events, EventState); ev.State != StateConnecting {
+		t.Fatalf("got %v, want Connecting", ev.State)
+	}
+	if ev = nextEvent(t, events, EventState); ev.State != StateAuthenticated {
+		t.Fatalf("got %v, want Authenticated", ev.State)
+	}
+	if out, err := client.SendCommand("gettime"); err != nil || out != "Day 7, 21:45\n" {
+		t.Errorf("gettime after reconnecting = %q, %v", out, err)
+	}
+}
+
+func TestReconnectBacksOff(t *testing.T) {
+	s := newFakeServer(t, "", func(c *fakeConn, cmd string) {})
+	client := connect(t, s)
+	events, cancel := client.Subscribe()
+	defer cancel()
+
+	// Gone for good, every retry fails
+	s.close()
+
+	for _, want := range []time.Duration{minBackoff, 2 * minBackoff} {
+		ev := nextEvent(t, events, EventState)
+		for ev.State != StateDisconnected {
+			ev = nextEvent(t, events, EventState)
+		}
+		if wait := time.Until(ev.RetryAt); wait < want-minBackoff/2 || wait > want {
+			t.Errorf("next retry in %v, want about %v", wait, want)
+		}
+	}
+}
+
+func TestWrongPassword(t *testing.T) {
+	s := newFakeServer(t, "secret", func(c *fakeConn, cmd string) {})
+
+	client := s.client(t, "guess")
+	if err := client.Connect(); !errors.Is(err, ErrAuthFailed) {
+		t.Fatalf("Connect = %v, want ErrAuthFailed", err)
+	}
+	if state, _ := client.State(); state != StateAuthFailed {
+		t.Errorf("state = %v, want AuthFailed", state)
+	}
+	if _, err := client.SendCommand("gettime"); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("SendCommand = %v, want ErrNotConnected", err)
+	}
+
+	client = s.client(t, "secret")
+	if err := client.Connect(); err != nil {
+		t.Errorf("Connect with the right password: %v", err)
+	}
+}
+
+func TestKeepConnectingWaitsForServer(t *testing.T) {
+	// Reserve a port, the server comes up on it later
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_, port, _ := net.SplitHostPort(addr)
+	ln.Close()
+
+	client := NewClient("127.0.0.1", port, "")
+	t.Cleanup(client.Close)
+	events, cancel := client.Subscribe()
+	defer cancel()
+
+	if err := client.KeepConnecting(); err == nil {
+		t.Fatal("KeepConnecting succeeded without a server")
+	}
+	if state, _ := client.State(); state != StateDisconnected {
+		t.Errorf("state = %v, want Disconnected", state)
+	}
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("port taken meanwhile: %v", err)
+	}
+	startFakeServer(t, ln, "", func(c *fakeConn, cmd string) { c.reply(cmd, "Day 7, 21:45") })
+
+	for nextEvent(t, events, EventState).State != StateAuthenticated {
+	}
+	if out, err := client.SendCommand("gettime"); err != nil || out != "Day 7, 21:45\n" {
+		t.Errorf("gettime = %q, %v", out, err)
+	}
+}
+
+func TestKeepConnectingGivesUpOnWrongPassword(t *testing.T) {
+	s := newFakeServer(t, "secret", func(c *fakeConn, cmd string) {})
+
+	client := s.client(t, "guess")
+	if err := client.KeepConnecting(); !errors.Is(err, ErrAuthFailed) {
+		t.Fatalf("KeepConnecting = %v, want ErrAuthFailed", err)
+	}
+
+	// Long enough for a retry
+	time.Sleep(minBackoff + minBackoff/2)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := len(s.open); n != 1 {
+		t.Errorf("%d connection attempts, want 1", n)
+	}
+}
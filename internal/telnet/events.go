@@ -0,0 +1,36 @@
+package telnet
+
+import "time"
+
+// EventType identifies what an Event carries
+type EventType int
+
+const (
+	EventLog   EventType = iota // Unsolicited server log line
+	EventState                  // Connection state change
+)
+
+// Event is published to subscribers by the client
+type Event struct {
+	Type EventType
+	Time time.Time
+	Line string // Raw log line for EventLog
+
+	// EventState fields
+	State   State
+	RetryAt time.Time // Next reconnect attempt while Disconnected
+	Err     error     // Why the connection was lost or refused
+}
+
+// Subscribe returns a channel receiving log lines and connection state
+// changes, and a function to stop the subscription. See pubsub.Hub.
+func (c *Client) Subscribe() (<-chan Event, func()) {
+	return c.subs.Subscribe()
+}
+
+func (c *Client) publish(ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	c.subs.Publish(ev)
+}
@@ -0,0 +1,80 @@
+package telnet
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// lineKind classifies a line received from the server
+type lineKind int
+
+const (
+	lineOutput lineKind = iota // Plain command output
+	lineEcho                   // "INF Executing command '...' by Telnet from ..."
+	lineLog                    // Any other timestamped server log line
+)
+
+// line is a single typed event produced by readLines
+type line struct {
+	kind lineKind
+	text string
+	cmd  string // Echoed command, only set for lineEcho
+}
+
+// Server log lines start with an ISO timestamp, e.g. "2025-12-10T10:35:08 1991.171 INF ..."
+var reLogPrefix = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2} `)
+
+// Commands whose output ends with a known line. Anything not listed here
+// is considered complete once the server has been quiet for quietPeriod.
+var reTotal = regexp.MustCompile(`^Total of \d+ `)
+
+var terminators = map[string]*regexp.Regexp{
+	"lp":               reTotal,
+	"lpi":              reTotal,
+	"listplayers":      reTotal,
+	"listplayerids":    reTotal,
+	"le":               reTotal,
+	"listents":         reTotal,
+	"lkp":              reTotal,
+	"listknownplayers": reTotal,
+	"llp":              reTotal,
+	"listlandclaims":   reTotal,
+	"gettime":          regexp.MustCompile(`^Day \d+, \d+:\d+`),
+	"gt":               regexp.MustCompile(`^Day \d+, \d+:\d+`),
+	"mem":              regexp.MustCompile(`FPS:`),
+}
+
+// terminatorFor returns the pattern marking the last line of cmd's output, or nil.
+func terminatorFor(cmd string) *regexp.Regexp {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		return nil
+	}
+	return terminators[strings.ToLower(fields[0])]
+}
+
+// readLines runs for the lifetime of a connection, turning the byte stream
+// into typed lines. The channel is closed when the connection fails.
+func readLines(r io.Reader, out chan<- line) {
+	defer close(out)
+
+	scanner := bufio.NewScanner(r)
+	// "le" on a busy server produces long lines
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+
+		l := line{kind: lineOutput, text: text}
+		if reLogPrefix.MatchString(text) {
+			l.kind = lineLog
+			if m := reEcho.FindStringSubmatch(text); m != nil {
+				l.kind = lineEcho
+				l.cmd = m[1]
+			}
+		}
+		out <- l
+	}
+}
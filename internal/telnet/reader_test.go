@@ -0,0 +1,63 @@
+package telnet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadLines(t *testing.T) {
+	long := "1. id=171, " + strings.Repeat("x", 100*1024)
+	input := "2025-12-10T10:35:08 1991.171 INF Executing command 'gettime' by Telnet from 10.0.2.12:38754\r\n" +
+		"Day 7, 21:45\r\n" +
+		"2025-12-10T10:35:09 1991.202 INF GMSG: Player 'Newbie' died\r\n" +
+		"  Banned until - UserID (name) - Reason\r\n" +
+		long + "\r\n" +
+		"\r\n" +
+		"no newline at the end"
+
+	want := []line{
+		{kind: lineEcho, text: "2025-12-10T10:35:08 1991.171 INF Executing command 'gettime' by Telnet from 10.0.2.12:38754", cmd: "gettime"},
+		{kind: lineOutput, text: "Day 7, 21:45"},
+		{kind: lineLog, text: "2025-12-10T10:35:09 1991.202 INF GMSG: Player 'Newbie' died"},
+		{kind: lineOutput, text: "  Banned until - UserID (name) - Reason"},
+		{kind: lineOutput, text: long},
+		{kind: lineOutput, text: ""},
+		{kind: lineOutput, text: "no newline at the end"},
+	}
+
+	out := make(chan line, 10)
+	readLines(strings.NewReader(input), out)
+	var got []line
+	for l := range out {
+		got = append(got, l)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readLines =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestTerminatorFor(t *testing.T) {
+	tests := []struct {
+		cmd      string
+		lastLine string // Empty if cmd has no terminator
+	}{
+		{cmd: "lp", lastLine: "Total of 3 in the game"},
+		{cmd: "LE", lastLine: "Total of 7 in the game"},
+		{cmd: "lkp -online", lastLine: "Total of 5 known"},
+		{cmd: "gettime", lastLine: "Day 7, 21:45"},
+		{cmd: "mem", lastLine: "Time: 32.55m FPS: 14.07 Heap: 1918.7MB Max: 1918.7MB Chunks: 249"},
+		{cmd: "ggs"},
+		{cmd: "say \"hello\""},
+		{cmd: ""},
+	}
+	for _, tt := range tests {
+		term := terminatorFor(tt.cmd)
+		switch {
+		case tt.lastLine == "" && term != nil:
+			t.Errorf("terminatorFor(%q) = %v, want none", tt.cmd, term)
+		case tt.lastLine != "" && (term == nil || !term.MatchString(tt.lastLine)):
+			t.Errorf("terminatorFor(%q) = %v, doesn't match %q", tt.cmd, term, tt.lastLine)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package telnet
+
+import "time"
+
+// State is the connection state of the client
+type State int
+
+const (
+	StateDisconnected State = iota
+	StateConnecting
+	StateAuthenticated
+	StateAuthFailed
+)
+
+func (s State) String() string {
+	switch s {
+	case StateConnecting:
+		return "Connecting"
+	case StateAuthenticated:
+		return "Authenticated"
+	case StateAuthFailed:
+		return "AuthFailed"
+	default:
+		return "Disconnected"
+	}
+}
+
+const (
+	minBackoff = 1 * time.Second
+	maxBackoff = 60 * time.Second
+)
+
+// State returns the current connection state and, while waiting to
+// reconnect, the time of the next attempt.
+func (c *Client) State() (State, time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state, c.retryAt
+}
+
+func (c *Client) setState(state State, retryAt time.Time, err error) {
+	c.mu.Lock()
+	c.state = state
+	c.retryAt = retryAt
+	c.mu.Unlock()
+
+	c.publish(Event{Type: EventState, State: state, RetryAt: retryAt, Err: err})
+}
+
+// nextBackoff doubles the delay up to maxBackoff
+func nextBackoff(delay time.Duration) time.Duration {
+	delay *= 2
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
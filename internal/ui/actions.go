@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"7dtd-monitor/internal/config"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// playerTarget is who a kick or ban dialog acts on. Only ID is required.
+type playerTarget struct {
+	ID         string // Anything the console accepts: entity ID, platform ID or name
+	Name       string
+	PlatformID string
+	IP         string
+}
+
+// actionDialog wraps form with a header describing the target
+func (a *App) actionDialog(title string, t playerTarget, form *tview.Form, height int) {
+	info := tview.NewTextView().SetDynamicColors(true)
+	if t.Name != "" {
+		fmt.Fprintf(info, " [yellow]%s[white]\n", tview.Escape(t.Name))
+		fmt.Fprintf(info, " Platform ID: %s\n", t.PlatformID)
+		fmt.Fprintf(info, " IP: %s", t.IP)
+	} else {
+		height -= 3
+	}
+
+	form.SetCancelFunc(a.closeModal)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow)
+	if t.Name != "" {
+		layout.AddItem(info, 3, 0, false)
+	}
+	layout.AddItem(form, 0, 1, true)
+	layout.SetBorder(true).SetTitle(title)
+
+	a.showModal(layout, 60, height)
+}
+
+// kickDialog picks a reason for kicking t, then asks for confirmation
+func (a *App) kickDialog(t playerTarget) {
+	reason := tview.NewInputField().SetLabel("Reason").SetFieldWidth(40)
+	presets := tview.NewDropDown().SetLabel("Preset")
+	presets.SetOptions(a.Actions.KickReasons, func(text string, _ int) {
+		reason.SetText(text)
+	})
+
+	form := tview.NewForm().
+		AddFormItem(presets).
+		AddFormItem(reason)
+	presets.SetCurrentOption(0)
+
+	form.AddButton("Kick", func() {
+		cmd := fmt.Sprintf("kick %s %s", t.ID, quoteArg(reason.GetText()))
+		a.confirm(fmt.Sprintf("Kick %s?\nReason: %s", targetLabel(t), reason.GetText()), func() {
+			a.runCommand(cmd, nil)
+		})
+	})
+	form.AddButton("Cancel", a.closeModal)
+
+	a.actionDialog(" Kick Player ", t, form, 14)
+}
+
+// banDialog picks a duration and reason for banning t, then asks for
+// confirmation. The player field is editable so it also works for players
+// who are not online.
+func (a *App) banDialog(t playerTarget) {
+	id := t.PlatformID
+	if id == "" {
+		id = t.ID
+	}
+	player := tview.NewInputField().SetLabel("Player").SetFieldWidth(40).SetText(id)
+	reason := tview.NewInputField().SetLabel("Reason").SetFieldWidth(40)
+	amount := tview.NewInputField().SetLabel("Duration").SetFieldWidth(6).SetAcceptanceFunc(tview.InputFieldInteger)
+	unit := tview.NewDropDown().SetLabel("Unit").SetOptions(config.BanUnits, nil)
+
+	reasonPresets := tview.NewDropDown().SetLabel("Reason preset")
+	reasonPresets.SetOptions(a.Actions.BanReasons, func(text string, _ int) {
+		reason.SetText(text)
+	})
+
+	var durations []string
+	for _, d := range a.Actions.BanDurations {
+		durations = append(durations, d.String())
+	}
+	durationPresets := tview.NewDropDown().SetLabel("Duration preset")
+	durationPresets.SetOptions(durations, func(_ string, i int) {
+		d := a.Actions.BanDurations[i]
+		amount.SetText(strconv.Itoa(d.Amount))
+		for j, u := range config.BanUnits {
+			if u == d.Unit {
+				unit.SetCurrentOption(j)
+			}
+		}
+	})
+
+	form := tview.NewForm().
+		AddFormItem(player).
+		AddFormItem(reasonPresets).
+		AddFormItem(reason).
+		AddFormItem(durationPresets).
+		AddFormItem(amount).
+		AddFormItem(unit)
+	reasonPresets.SetCurrentOption(0)
+	// Days unless a preset picks another unit
+	unit.SetCurrentOption(slices.Index(config.BanUnits, "days"))
+	durationPresets.SetCurrentOption(0)
+
+	form.AddButton("Ban", func() {
+		id := strings.TrimSpace(player.GetText())
+		_, u := unit.GetCurrentOption()
+		if id == "" || amount.GetText() == "" || u == "" {
+			return
+		}
+		target := t
+		if id != t.PlatformID && id != t.ID {
+			target = playerTarget{ID: id}
+		}
+
+		cmd := fmt.Sprintf("ban add %s %s %s", id, amount.GetText(), u)
+		if strings.TrimSpace(reason.GetText()) != "" {
+			cmd += " " + quoteArg(reason.GetText())
+		}
+		a.confirm(fmt.Sprintf("Ban %s for %s %s?\nReason: %s", targetLabel(target), amount.GetText(), u, reason.GetText()), func() {
+			a.runCommand(cmd, a.Collector.RefreshBans)
+		})
+	})
+	form.AddButton("Cancel", a.closeModal)
+
+	a.actionDialog(" Ban Player ", t, form, 20)
+}
+
+// targetLabel names t for confirmation messages
+func targetLabel(t playerTarget) string {
+	if t.Name == "" {
+		return t.ID
+	}
+	return fmt.Sprintf("%s (%s, %s)", t.Name, t.PlatformID, t.IP)
+}
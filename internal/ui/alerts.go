@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"7dtd-monitor/internal/alert"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// AlertBanner is a line above the pages listing the firing alerts. It
+// flashes while any are firing and takes no room otherwise.
+type AlertBanner struct {
+	*tview.TextView
+	firing []alert.Alert
+	lit    bool // Flash phase
+}
+
+func NewAlertBanner() *AlertBanner {
+	b := &AlertBanner{TextView: tview.NewTextView().SetDynamicColors(true)}
+	b.SetWrap(false)
+	return b
+}
+
+// Height is the number of rows the banner needs
+func (b *AlertBanner) Height() int {
+	if len(b.firing) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// Update shows the alerts firing now
+func (b *AlertBanner) Update(firing []alert.Alert) {
+	b.firing = firing
+	if len(firing) == 0 {
+		b.Clear()
+		return
+	}
+
+	parts := make([]string, len(firing))
+	for i, a := range firing {
+		parts[i] = tview.Escape(a.String())
+	}
+	b.SetText(" ALERT  " + strings.Join(parts, "  |  "))
+	b.lit = true
+	b.paint()
+}
+
+// Flash toggles the banner colors, called every second
+func (b *AlertBanner) Flash() {
+	if len(b.firing) == 0 {
+		return
+	}
+	b.lit = !b.lit
+	b.paint()
+}
+
+func (b *AlertBanner) paint() {
+	color := tcell.ColorOrange
+	for _, a := range b.firing {
+		if a.Severity == "critical" {
+			color = tcell.ColorRed
+		}
+	}
+	if b.lit {
+		b.SetBackgroundColor(color)
+		b.SetTextColor(tcell.ColorBlack)
+	} else {
+		b.SetBackgroundColor(tview.Styles.PrimitiveBackgroundColor)
+		b.SetTextColor(color)
+	}
+}
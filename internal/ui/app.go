@@ -1,8 +1,14 @@
 package ui
 
 import (
+	"7dtd-monitor/internal/alert"
+	"7dtd-monitor/internal/collector"
+	"7dtd-monitor/internal/config"
+	"7dtd-monitor/internal/events"
+	"7dtd-monitor/internal/metrics"
 	"7dtd-monitor/internal/model" // Added for model.Player
 	"7dtd-monitor/internal/parser"
+	"7dtd-monitor/internal/store"
 	"7dtd-monitor/internal/telnet"
 	"fmt"
 	"strings"
@@ -19,12 +25,52 @@ type App struct {
 	PlayersTable *tview.Table
 	LogView      *tview.TextView
 	Input        *tview.InputField
+	Pages        *tview.Pages
+	Footer       *tview.TextView
+	ConfigView   *ConfigView
+	KnownView    *KnownPlayersView
+	ClaimsView   *LandClaimsView
+	BansView     *BansView
+	MapView      *MapView
+	Proximity    *tview.TextView
+	MetricsView  *MetricsView
+	Banner       *AlertBanner
+
+	Stream    *events.Stream
+	Collector *collector.Collector
+	Known     *store.KnownPlayers
+	History   *store.History
+	Metrics   *metrics.Ring
+	Actions   config.Actions // Presets for the kick and ban dialogs
+	Alerts    *alert.Engine  // Optional
+
+	statsBody string      // Last polled stats, shown below the connection status
+	bans      []model.Ban // Last polled ban list
+	pages     []page
+	layout    *tview.Flex // Root, to resize the alert banner
+
+	modalReturn tview.Primitive // Focused before the open dialog, if any
+	detail      *PlayerDetail   // Open player detail pane, if any
 }
 
-func NewApp(client *telnet.Client) *App {
+// page is a full screen view switched to with a function key
+type page struct {
+	name  string
+	key   tcell.Key
+	focus tview.Primitive
+}
+
+func NewApp(client *telnet.Client, stream *events.Stream, coll *collector.Collector, known *store.KnownPlayers, actions config.Actions, ring *metrics.Ring, alerts *alert.Engine) *App {
 	app := &App{
-		TviewApp: tview.NewApplication(),
-		Client:   client,
+		TviewApp:  tview.NewApplication(),
+		Client:    client,
+		Stream:    stream,
+		Collector: coll,
+		Known:     known,
+		History:   store.NewHistory(),
+		Actions:   actions,
+		Metrics:   ring,
+		Alerts:    alerts,
 	}
 	app.setupUI()
 	return app
@@ -42,7 +88,7 @@ func (a *App) setupUI() {
 	a.PlayersTable = tview.NewTable().
 		SetBorders(true).
 		SetSelectable(true, false) // Enable row selection
-	a.PlayersTable.SetBorder(true).SetTitle(" Online Players ")
+	a.PlayersTable.SetBorder(true).SetTitle(" Online Players (Enter: details, k: kick, b: ban, t: teleport, m: map) ")
 
 	// 3. Log/Debug View (bottom)
 	a.LogView = tview.NewTextView().
@@ -65,36 +111,33 @@ func (a *App) setupUI() {
 				return
 			}
 			a.Input.SetText("") // Clear
-
-			// Exec Async
-			go func() {
-				a.TviewApp.QueueUpdateDraw(func() {
-					a.LogView.Write([]byte(fmt.Sprintf("[yellow]> %s[white]\n", cmd)))
-				})
-
-				resp, err := a.Client.SendCommand(cmd)
-				if err != nil {
-					a.TviewApp.QueueUpdateDraw(func() {
-						a.LogView.Write([]byte(fmt.Sprintf("[red]Error: %v[white]\n", err)))
-						a.LogView.ScrollToEnd()
-					})
-				} else {
-					a.TviewApp.QueueUpdateDraw(func() {
-						_, logs := parser.SplitLogs(resp)
-						for _, l := range logs {
-							a.LogView.Write([]byte(fmt.Sprintf("[gray]%s[white]\n", l)))
-						}
-						clean, _ := parser.SplitLogs(resp)
-						if clean != "" {
-							a.LogView.Write([]byte(fmt.Sprintf("%s\n", clean)))
-						}
-						a.LogView.ScrollToEnd()
-					})
-				}
-			}()
+			a.runCommand(cmd, nil)
 		}
 	})
 
+	// 5. Server Config
+	a.ConfigView = NewConfigView()
+
+	// 6. Known Players
+	a.KnownView = NewKnownPlayersView(a.TviewApp, a.Known, a.banKnown, a.unbanKnown)
+
+	// 7. Land Claims
+	a.ClaimsView = NewLandClaimsView(a.Known)
+
+	// 8. Bans & Admins
+	a.BansView = NewBansView(a)
+
+	// 9. Map
+	a.MapView = NewMapView()
+	a.Proximity = tview.NewTextView().SetDynamicColors(true)
+	a.Proximity.SetBorder(true).SetTitle(" Nearby ")
+	mapPage := tview.NewFlex().
+		AddItem(a.MapView, 0, 1, true).
+		AddItem(a.Proximity, 40, 0, false)
+
+	// 10. Metrics
+	a.MetricsView = NewMetricsView(a.Metrics)
+
 	// Layout: Flex
 	// Top: Stats (Fixed Height?), Middle: Log/Players, Bottom: Input
 	// Let's go with:
@@ -106,196 +149,419 @@ func (a *App) setupUI() {
 		AddItem(a.StatsText, 0, 1, false).
 		AddItem(a.PlayersTable, 0, 2, true) // Focus table by default?
 
-	mainFlex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(topFlex, 15, 1, false).
-		AddItem(a.LogView, 0, 1, false).
-		AddItem(a.Input, 3, 1, true)
+	dashboard := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(topFlex, 20, 1, false).
+		AddItem(a.LogView, 0, 1, false)
+
+	// Pages share the console and a footer listing the function keys
+	a.Pages = tview.NewPages()
+	a.addPage("Dashboard", tcell.KeyF1, dashboard, a.PlayersTable)
+	a.addPage("Server Config", tcell.KeyF2, a.ConfigView, a.ConfigView)
+	a.addPage("Known Players", tcell.KeyF3, a.KnownView, a.KnownView.Table)
+	a.addPage("Land Claims", tcell.KeyF4, a.ClaimsView, a.ClaimsView.Table)
+	a.addPage("Bans & Admins", tcell.KeyF5, a.BansView, a.BansView.Bans)
+	a.addPage("Map", tcell.KeyF6, mapPage, a.MapView)
+	a.addPage("Metrics", tcell.KeyF7, a.MetricsView, a.MetricsView)
+
+	a.Footer = tview.NewTextView().SetDynamicColors(true)
+	a.updateFooter()
+
+	a.Banner = NewAlertBanner()
+
+	a.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.Banner, 0, 0, false).
+		AddItem(a.Pages, 0, 1, false).
+		AddItem(a.Input, 3, 1, true).
+		AddItem(a.Footer, 1, 1, false)
+
+	a.TviewApp.SetInputCapture(a.globalKeys)
+	a.TviewApp.SetRoot(a.layout, true).SetFocus(a.Input)
+}
 
-	a.TviewApp.SetRoot(mainFlex, true).SetFocus(a.Input)
+// banKnown opens the ban dialog for a player who may be offline
+func (a *App) banKnown(p model.KnownPlayer) {
+	a.banDialog(playerTarget{ID: p.PlatformID, Name: p.Name, PlatformID: p.PlatformID, IP: p.IP})
 }
 
-func (a *App) Run() error {
-	// Start refresh loop
-	go a.refreshLoop()
+func (a *App) unbanKnown(p model.KnownPlayer) {
+	a.confirm(fmt.Sprintf("Unban %s (%s)?", p.Name, p.PlatformID), func() {
+		a.runCommand("ban remove "+p.PlatformID, a.Collector.RefreshBans)
+	})
+}
+
+// addPage registers a page; focus is what Tab switches to on that page.
+func (a *App) addPage(name string, key tcell.Key, content, focus tview.Primitive) {
+	a.Pages.AddPage(name, content, true, len(a.pages) == 0)
+	a.pages = append(a.pages, page{name: name, key: key, focus: focus})
+}
 
-	if err := a.Client.Connect(); err != nil {
-		return err
+func (a *App) updateFooter() {
+	current, _ := a.Pages.GetFrontPage()
+	var parts []string
+	for _, p := range a.pages {
+		label := fmt.Sprintf("%s %s", tcell.KeyNames[p.key], p.name)
+		if p.name == current {
+			label = "[black:yellow]" + label + "[-:-]"
+		}
+		parts = append(parts, label)
 	}
+	parts = append(parts, "Tab Focus")
+	a.Footer.SetText(" " + strings.Join(parts, "  "))
+}
 
-	return a.TviewApp.Run()
+// showPage switches to the named page and focuses it
+func (a *App) showPage(name string) {
+	for _, p := range a.pages {
+		if p.name == name {
+			a.Pages.SwitchToPage(p.name)
+			a.TviewApp.SetFocus(p.focus)
+			a.updateFooter()
+		}
+	}
 }
 
-func (a *App) refreshLoop() {
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+// globalKeys switches pages with function keys and toggles focus between
+// the current page and the console with Tab.
+func (a *App) globalKeys(event *tcell.EventKey) *tcell.EventKey {
+	// Dialogs get every key until they are closed
+	if a.Pages.HasPage(modalPage) {
+		return event
+	}
+
+	for _, p := range a.pages {
+		if event.Key() == p.key {
+			a.showPage(p.name)
+			return nil
+		}
+	}
 
-	for {
-		<-ticker.C
-		a.updateData()
+	if event.Key() == tcell.KeyTab {
+		if a.Input.HasFocus() {
+			current, _ := a.Pages.GetFrontPage()
+			for _, p := range a.pages {
+				if p.name == current {
+					a.TviewApp.SetFocus(p.focus)
+				}
+			}
+		} else {
+			a.TviewApp.SetFocus(a.Input)
+		}
+		return nil
 	}
+	return event
 }
 
-func (a *App) processResponse(cmd string) (string, error) {
-	raw, err := a.Client.SendCommand(cmd)
-	if err != nil {
-		return "", err
+// Run starts the TUI. The client is expected to be connected already.
+func (a *App) Run() error {
+	lines, cancelLines := a.Client.Subscribe()
+	defer cancelLines()
+	gameEvents, cancelEvents := a.Stream.Subscribe()
+	defer cancelEvents()
+	updates, cancelUpdates := a.Collector.Subscribe()
+	defer cancelUpdates()
+	var alerts <-chan alert.Alert // Stays nil without an engine, never ready
+	if a.Alerts != nil {
+		var cancelAlerts func()
+		alerts, cancelAlerts = a.Alerts.Subscribe()
+		defer cancelAlerts()
 	}
-	clean, logs := parser.SplitLogs(raw)
+	go a.eventLoop(lines, gameEvents, updates, alerts)
 
-	// Append logs to view safely
-	a.TviewApp.QueueUpdateDraw(func() {
-		for _, l := range logs {
-			if isImportantLog(l) {
-				// Colorize?
-				color := "[gray]"
-				if strings.Contains(l, "ERR") {
-					color = "[red]"
-				} else if strings.Contains(l, "Chat") {
-					color = "[green]"
-				} else if strings.Contains(l, "WRN") {
-					color = "[yellow]"
-				}
+	return a.TviewApp.Run()
+}
+
+// eventLoop shows polled data, unsolicited server log lines, connection
+// state changes and alerts as they arrive, and records game events in the
+// player history.
+func (a *App) eventLoop(lines <-chan telnet.Event, gameEvents <-chan events.Event, updates <-chan collector.Update, alerts <-chan alert.Alert) {
+	// Keeps the reconnect countdown moving and the alert banner flashing
+	countdown := time.NewTicker(time.Second)
+	defer countdown.Stop()
+	// Kept here, the banner itself may only be touched on the UI goroutine
+	flashing := false
 
-				a.LogView.Write([]byte(fmt.Sprintf("%s%s[white]\n", color, l)))
+	for {
+		select {
+		case u, ok := <-updates:
+			if !ok {
+				return
+			}
+			if u.Type == collector.UpdateStats {
+				a.History.UpdatePlayers(u.Snapshot.Players, u.Time)
+			}
+			a.TviewApp.QueueUpdateDraw(func() {
+				a.applyUpdate(u)
+			})
+		case ev, ok := <-gameEvents:
+			if !ok {
+				return
+			}
+			a.History.Record(ev)
+		case ev, ok := <-lines:
+			if !ok {
+				return
+			}
+			switch ev.Type {
+			case telnet.EventLog:
+				line := ev.Line
+				a.TviewApp.QueueUpdateDraw(func() {
+					a.writeLogs([]string{line})
+				})
+			case telnet.EventState:
+				a.TviewApp.QueueUpdateDraw(a.renderStats)
+			}
+		case al, ok := <-alerts:
+			if !ok {
+				return
+			}
+			firing := a.Alerts.Firing()
+			flashing = len(firing) > 0
+			a.TviewApp.QueueUpdateDraw(func() {
+				a.showAlert(al, firing)
+			})
+		case <-countdown.C:
+			if state, _ := a.Client.State(); state == telnet.StateDisconnected {
+				a.TviewApp.QueueUpdateDraw(a.renderStats)
+			}
+			if flashing {
+				a.TviewApp.QueueUpdateDraw(a.Banner.Flash)
 			}
 		}
-		a.LogView.ScrollToEnd()
-	})
+	}
+}
 
-	return clean, nil
+// applyUpdate shows a collector update. Must run on the UI goroutine.
+func (a *App) applyUpdate(u collector.Update) {
+	switch u.Type {
+	case collector.UpdateStats:
+		a.showSnapshot(u.Snapshot)
+	case collector.UpdatePrefs:
+		a.ConfigView.Update(u.Prefs)
+	case collector.UpdateKnownPlayers:
+		if u.Err != nil {
+			a.LogView.Write([]byte(fmt.Sprintf("[red]Error saving known players: %v[white]\n", u.Err)))
+		}
+		a.KnownView.Refresh()
+	case collector.UpdateLandClaims:
+		a.ClaimsView.Update(u.Claims)
+		a.MapView.SetClaims(u.Claims)
+	case collector.UpdateBans:
+		a.bans = u.Bans
+		a.BansView.Update(u.Bans, u.Admins)
+	}
 }
 
-func (a *App) updateData() {
-	// 1. Get Time
-	timeStr, _ := a.processResponse("gettime")
-	gameTime := parser.ParseTime(timeStr)
+// showAlert logs an alert change and updates the banner. Must run on the UI goroutine.
+func (a *App) showAlert(al alert.Alert, firing []alert.Alert) {
+	label := "[orange]ALERT"
+	if al.State == alert.Resolved {
+		label = "[green]RESOLVED"
+	}
+	a.LogView.Write([]byte(fmt.Sprintf("%s[white] %s\n", label, tview.Escape(al.String()))))
+	a.LogView.ScrollToEnd()
 
-	// 2. Get Mem & FPS
-	memStr, _ := a.processResponse("mem")
-	heap, max, fps := parser.ParseMem(memStr)
+	a.Banner.Update(firing)
+	a.layout.ResizeItem(a.Banner, a.Banner.Height(), 0)
+}
 
-	// 3. Get Players
-	playersStr, _ := a.processResponse("lp")
-	players, _ := parser.ParsePlayers(playersStr)
+// renderStats redraws the Server Stats panel. Must run on the UI goroutine.
+func (a *App) renderStats() {
+	a.StatsText.SetText(a.connectionStatus() + a.statsBody)
+}
 
-	// 4. Get Entities
-	entStr, _ := a.processResponse("le")
-	zombies, animals, _ := parser.ParseEntities(entStr)
+func (a *App) connectionStatus() string {
+	state, retryAt := a.Client.State()
+	switch state {
+	case telnet.StateAuthenticated:
+		return "\n [green]Status:[white] Connected\n"
+	case telnet.StateConnecting:
+		return "\n [yellow]Status:[white] Connecting...\n"
+	case telnet.StateAuthFailed:
+		return "\n [red]Status:[white] Authentication failed\n"
+	}
 
-	// Calculate Avg Ping
-	var totalPing int
-	for _, p := range players {
-		totalPing += p.Ping
+	status := "\n [red]Status:[white] Disconnected"
+	if !retryAt.IsZero() {
+		wait := time.Until(retryAt).Round(time.Second)
+		if wait < 0 {
+			wait = 0
+		}
+		status += fmt.Sprintf(" (retry in %s)", wait)
 	}
-	avgPing := 0
-	if len(players) > 0 {
-		avgPing = totalPing / len(players)
+	return status + "\n"
+}
+
+// writeLogs appends the important lines to LogView. Must run on the UI goroutine.
+func (a *App) writeLogs(logs []string) {
+	for _, raw := range logs {
+		l, ok := parser.ParseLogLine(raw)
+		if !ok || !isImportantLog(l) {
+			continue
+		}
+
+		color := "[gray]"
+		switch {
+		case l.Level == "ERR" || l.Level == "EXC":
+			color = "[red]"
+		case l.Level == "WRN":
+			color = "[yellow]"
+		case strings.HasPrefix(l.Message, "Chat"):
+			color = "[green]"
+		}
+
+		a.LogView.Write([]byte(fmt.Sprintf("%s%s[white]\n", color, tview.Escape(l.Raw))))
 	}
+	a.LogView.ScrollToEnd()
+}
 
+// ReportError shows a failure of a background task in LogView. Safe to call
+// from any goroutine.
+func (a *App) ReportError(doing string, err error) {
+	msg := fmt.Sprintf("[red]Error %s: %s[white]\n", doing, tview.Escape(err.Error()))
 	a.TviewApp.QueueUpdateDraw(func() {
-		// Update Stats
-		statsText := fmt.Sprintf("\n [green]Host:[white] %s\n [green]Port:[white] %s\n\n [yellow]Game Time:[white] %s\n [yellow]Server FPS:[white] %s\n\n [blue]Heap:[white] %s / %s\n [blue]Players:[white] %d\n [blue]Avg Ping:[white] %d ms\n\n [red]Zombies:[white] %d\n [green]Animals:[white] %d",
-			a.Client.Host, a.Client.Port, gameTime, fps, heap, max, len(players), avgPing, zombies, animals)
-		a.StatsText.SetText(statsText)
-
-		// Update Table
-		a.PlayersTable.Clear()
-		headers := []string{"ID", "Name", "Score", "Lvl", "Z-Kills", "P-Kills", "Deaths", "Ping", "IP"}
-		for i, h := range headers {
-			a.PlayersTable.SetCell(0, i,
-				tview.NewTableCell(h).
-					SetTextColor(tview.Styles.SecondaryTextColor).
-					SetAlign(tview.AlignCenter).
-					SetSelectable(false))
-		}
+		a.LogView.Write([]byte(msg))
+		a.LogView.ScrollToEnd()
+	})
+}
 
-		for i, p := range players {
-			row := i + 1
-			// Helper to make cells
-			c := func(text string) *tview.TableCell {
-				return tview.NewTableCell(text).SetTextColor(tview.Styles.PrimaryTextColor)
-			}
-			center := func(text string) *tview.TableCell {
-				return tview.NewTableCell(text).SetTextColor(tview.Styles.PrimaryTextColor).SetAlign(tview.AlignCenter)
-			}
+// showSnapshot renders a stats poll. Must run on the UI goroutine.
+func (a *App) showSnapshot(snap collector.Snapshot) {
+	stats, bloodMoon, players, entities := snap.Stats, snap.BloodMoon, snap.Players, snap.Entities
+	zombies, animals := snap.Counts[model.CategoryZombie], snap.Counts[model.CategoryAnimal]
+	avgPing := snap.AvgPing
+
+	// Update Stats
+	a.statsBody = fmt.Sprintf(" [green]Host:[white] %s\n [green]Port:[white] %s\n\n [yellow]Game Time:[white] %s\n [yellow]Blood Moon:[white] %s\n [yellow]Server FPS:[white] %.1f\n [yellow]Uptime:[white] %s\n\n [blue]Heap:[white] %.0f / %.0f MB\n [blue]RSS:[white] %.0f MB\n [blue]Chunks:[white] %d  [blue]Entities:[white] %d (%d)\n [blue]Players:[white] %d\n [blue]Avg Ping:[white] %d ms\n\n [red]Zombies:[white] %d\n [green]Animals:[white] %d",
+		stats.Host, a.Client.Port, stats.Time, formatBloodMoon(bloodMoon), stats.Mem.FPS, stats.Uptime.Round(time.Minute), stats.Mem.HeapMB, stats.Mem.MaxMB, stats.Mem.RSSMB,
+		stats.Mem.Chunks, stats.Mem.Entities, stats.Mem.EntitiesTotal, stats.PlayerCount, avgPing, zombies, animals)
+	a.renderStats()
+
+	a.MapView.SetPositions(players, entities)
+	a.Proximity.SetText(a.MapView.Proximity())
+
+	// Horde night turns the whole panel red
+	if bloodMoon.Active {
+		a.StatsText.SetBorderColor(tcell.ColorRed).SetTitle(" Server Stats - HORDE NIGHT ")
+	} else {
+		a.StatsText.SetBorderColor(tview.Styles.BorderColor).SetTitle(" Server Stats ")
+	}
+
+	// Update Table
+	a.PlayersTable.Clear()
+	headers := []string{"ID", "Name", "Score", "Lvl", "Z-Kills", "P-Kills", "Deaths", "Ping", "IP"}
+	for i, h := range headers {
+		a.PlayersTable.SetCell(0, i,
+			tview.NewTableCell(h).
+				SetTextColor(tview.Styles.SecondaryTextColor).
+				SetAlign(tview.AlignCenter).
+				SetSelectable(false))
+	}
 
-			// Store Player Struct or ID in the reference for actions
-			// We store ID in the first cell
-			idCell := c(p.ID)
-			idCell.SetReference(p) // Store full player object
-
-			a.PlayersTable.SetCell(row, 0, idCell)
-			a.PlayersTable.SetCell(row, 1, c(p.Name))
-			a.PlayersTable.SetCell(row, 2, center(fmt.Sprintf("%d", p.Score)))
-			a.PlayersTable.SetCell(row, 3, center(fmt.Sprintf("%d", p.Level)))
-			a.PlayersTable.SetCell(row, 4, center(fmt.Sprintf("%d", p.Zombies)))     // Z-Kills
-			a.PlayersTable.SetCell(row, 5, center(fmt.Sprintf("%d", p.PlayerKills))) // P-Kills
-			a.PlayersTable.SetCell(row, 6, center(fmt.Sprintf("%d", p.Deaths)))      // Deaths
-			a.PlayersTable.SetCell(row, 7, center(fmt.Sprintf("%d", p.Ping)))
-			a.PlayersTable.SetCell(row, 8, c(p.IP))
+	for i, p := range players {
+		row := i + 1
+		// Helper to make cells
+		c := func(text string) *tview.TableCell {
+			return tview.NewTableCell(text).SetTextColor(tview.Styles.PrimaryTextColor)
+		}
+		center := func(text string) *tview.TableCell {
+			return tview.NewTableCell(text).SetTextColor(tview.Styles.PrimaryTextColor).SetAlign(tview.AlignCenter)
 		}
 
-		// Ensure selection behavior
-		a.PlayersTable.SetSelectable(true, false)
+		// Store Player Struct or ID in the reference for actions
+		// We store ID in the first cell
+		idCell := c(p.ID)
+		idCell.SetReference(p) // Store full player object
+
+		a.PlayersTable.SetCell(row, 0, idCell)
+		a.PlayersTable.SetCell(row, 1, c(p.Name))
+		a.PlayersTable.SetCell(row, 2, center(fmt.Sprintf("%d", p.Score)))
+		a.PlayersTable.SetCell(row, 3, center(fmt.Sprintf("%d", p.Level)))
+		a.PlayersTable.SetCell(row, 4, center(fmt.Sprintf("%d", p.Zombies)))     // Z-Kills
+		a.PlayersTable.SetCell(row, 5, center(fmt.Sprintf("%d", p.PlayerKills))) // P-Kills
+		a.PlayersTable.SetCell(row, 6, center(fmt.Sprintf("%d", p.Deaths)))      // Deaths
+		a.PlayersTable.SetCell(row, 7, center(fmt.Sprintf("%d", p.Ping)))
+		a.PlayersTable.SetCell(row, 8, c(p.IP))
+	}
 
-		a.PlayersTable.SetSelectedFunc(func(row, column int) {
-			// Enter on row does nothing or maybe shows info?
-		})
+	// Ensure selection behavior
+	a.PlayersTable.SetSelectable(true, false)
 
-		a.PlayersTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-			r, _ := a.PlayersTable.GetSelection()
-			if r <= 0 { // Ignore header row
-				return event
-			}
-			// Get player from cell 0 reference
-			cell := a.PlayersTable.GetCell(r, 0)
-			if cell == nil {
-				return event
-			}
-			ref := cell.GetReference()
-			if ref == nil {
-				return event
-			}
-			p := ref.(model.Player) // Correctly cast to model.Player
-
-			switch event.Rune() {
-			case 'k':
-				a.Input.SetText(fmt.Sprintf("kick %s \"Kicked by Console\"", p.ID))
-				a.TviewApp.SetFocus(a.Input)
-			case 'b':
-				a.Input.SetText(fmt.Sprintf("ban %s 10 year \"Banned by Console\"", p.ID))
-				a.TviewApp.SetFocus(a.Input)
-			case 't':
-				a.Input.SetText(fmt.Sprintf("teleport %s ", p.ID))
-				a.TviewApp.SetFocus(a.Input)
+	a.PlayersTable.SetSelectedFunc(func(row, column int) {
+		if p, ok := a.PlayersTable.GetCell(row, 0).GetReference().(model.Player); ok {
+			a.detail = NewPlayerDetail(a, p)
+			a.showModal(a.detail, 100, 40)
+		}
+	})
+
+	// Keep an open detail pane current
+	if a.detail != nil {
+		for _, p := range players {
+			if p.PlatformID == a.detail.player.PlatformID {
+				a.detail.Refresh(p)
 			}
+		}
+	}
+
+	a.PlayersTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		r, _ := a.PlayersTable.GetSelection()
+		if r <= 0 { // Ignore header row
+			return event
+		}
+		// Get player from cell 0 reference
+		cell := a.PlayersTable.GetCell(r, 0)
+		if cell == nil {
+			return event
+		}
+		ref := cell.GetReference()
+		if ref == nil {
 			return event
-		})
+		}
+		p := ref.(model.Player) // Correctly cast to model.Player
+
+		target := playerTarget{ID: p.ID, Name: p.Name, PlatformID: p.PlatformID, IP: p.IP}
+		switch event.Rune() {
+		case 'k':
+			a.kickDialog(target)
+			return nil
+		case 'b':
+			a.banDialog(target)
+			return nil
+		case 't':
+			a.teleportDialog(target)
+			return nil
+		case 'm':
+			a.MapView.Follow(p.PlatformID)
+			a.showPage("Map")
+			return nil
+		}
+		return event
 	})
 }
 
-func isImportantLog(log string) bool {
-	// Filter for Chat, Errors, Warnings, Player Activity
-	// Chat in 7DTD: "Chat (from 'SteamId'): <Name>: Message"
-	// or similar patterns.
-	// We want to keep it simple but effective.
-
-	// Keywords to Keep
-	keywords := []string{
-		"Chat",
-		"PlayerConnected",
-		"PlayerDisconnected",
-		"ERR",
-		"WRN",
-		"Kicked",
-		"Banned",
+func formatBloodMoon(bm model.BloodMoonStatus) string {
+	switch {
+	case !bm.Enabled:
+		return "disabled"
+	case bm.Active:
+		return fmt.Sprintf("[red]ACTIVE[white] (ends in %s)", formatCountdown(bm.Until))
 	}
+	return fmt.Sprintf("Day %d in %s", bm.Day, formatCountdown(bm.Until))
+}
 
-	for _, kw := range keywords {
-		if strings.Contains(log, kw) {
-			return true
-		}
+// formatCountdown prints durations as "1h12m" or "4m30s"
+func formatCountdown(d time.Duration) string {
+	if d >= time.Hour {
+		return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
 	}
+	return d.Round(time.Second).String()
+}
 
-	return false
+func isImportantLog(l parser.LogLine) bool {
+	// Keep Errors, Warnings and anything that is a game event
+	// (Chat, Player Activity, Kicks, Bans...)
+	if l.IsProblem() {
+		return true
+	}
+	_, ok := events.Parse(l)
+	return ok
 }
@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"7dtd-monitor/internal/model"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// BansView is the "Bans & Admins" page: the server's ban list next to the
+// admin list, with dialogs to change both.
+type BansView struct {
+	*tview.Flex
+	Bans   *tview.Table
+	Admins *tview.Table
+
+	app *App
+}
+
+func NewBansView(app *App) *BansView {
+	v := &BansView{
+		Bans:   tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		Admins: tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		app:    app,
+	}
+	v.Bans.SetBorder(true).SetTitle(" Bans (a: add, d: unban, →: admins) ")
+	v.Admins.SetBorder(true).SetTitle(" Admins (a: add, l: level, d: remove, ←: bans) ")
+	v.Bans.SetCell(0, 0, tview.NewTableCell("Waiting for ban list...").SetSelectable(false))
+	v.Admins.SetCell(0, 0, tview.NewTableCell("Waiting for admin list...").SetSelectable(false))
+
+	v.Bans.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRight {
+			v.app.TviewApp.SetFocus(v.Admins)
+			return nil
+		}
+		switch event.Rune() {
+		case 'a':
+			v.app.banDialog(playerTarget{})
+			return nil
+		case 'd':
+			if b, ok := v.selectedBan(); ok {
+				v.app.confirm(fmt.Sprintf("Unban %s (%s)?", b.Name, b.PlatformID), func() {
+					v.app.runCommand("ban remove "+b.PlatformID, v.app.Collector.RefreshBans)
+				})
+			}
+			return nil
+		}
+		return event
+	})
+
+	v.Admins.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyLeft {
+			v.app.TviewApp.SetFocus(v.Bans)
+			return nil
+		}
+		switch event.Rune() {
+		case 'a':
+			v.adminDialog(model.Admin{Level: 1000})
+			return nil
+		case 'l':
+			if admin, ok := v.selectedAdmin(); ok {
+				v.adminDialog(admin)
+			}
+			return nil
+		case 'd':
+			if admin, ok := v.selectedAdmin(); ok {
+				v.app.confirm(fmt.Sprintf("Remove %s (%s) from the admins?", admin.Name, admin.PlatformID), func() {
+					v.app.runCommand("admin remove "+admin.PlatformID, v.app.Collector.RefreshBans)
+				})
+			}
+			return nil
+		}
+		return event
+	})
+
+	v.Flex = tview.NewFlex().
+		AddItem(v.Bans, 0, 3, true).
+		AddItem(v.Admins, 0, 2, false)
+	return v
+}
+
+func (v *BansView) selectedBan() (model.Ban, bool) {
+	row, _ := v.Bans.GetSelection()
+	b, ok := v.Bans.GetCell(row, 0).GetReference().(model.Ban)
+	return b, ok
+}
+
+func (v *BansView) selectedAdmin() (model.Admin, bool) {
+	row, _ := v.Admins.GetSelection()
+	admin, ok := v.Admins.GetCell(row, 0).GetReference().(model.Admin)
+	return admin, ok
+}
+
+// Update shows the lists. Must run on the UI goroutine.
+func (v *BansView) Update(bans []model.Ban, admins []model.Admin) {
+	header := func(t *tview.Table, headers ...string) {
+		t.Clear()
+		for i, h := range headers {
+			t.SetCell(0, i, tview.NewTableCell(h).
+				SetTextColor(tview.Styles.SecondaryTextColor).
+				SetSelectable(false))
+		}
+	}
+
+	// Sorted copies, the slices are shared with other subscribers
+	bans = slices.Clone(bans)
+	admins = slices.Clone(admins)
+
+	header(v.Bans, "Name", "Platform ID", "Until", "Reason")
+	sort.SliceStable(bans, func(i, j int) bool { return bans[i].Until.Before(bans[j].Until) })
+	for i, b := range bans {
+		until := b.UntilRaw
+		if !b.Until.IsZero() {
+			until = b.Until.Format("2006-01-02 15:04")
+			if left := time.Until(b.Until); left > 0 {
+				until += fmt.Sprintf(" (%s)", formatAgo(left))
+			} else {
+				until = "[gray]" + until + " (expired)[white]"
+			}
+		}
+		v.Bans.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(b.Name)).SetReference(b))
+		v.Bans.SetCell(i+1, 1, tview.NewTableCell(b.PlatformID))
+		v.Bans.SetCell(i+1, 2, tview.NewTableCell(until))
+		v.Bans.SetCell(i+1, 3, tview.NewTableCell(tview.Escape(b.Reason)).SetExpansion(1))
+	}
+
+	header(v.Admins, "Level", "Name", "Platform ID")
+	sort.SliceStable(admins, func(i, j int) bool { return admins[i].Level < admins[j].Level })
+	for i, admin := range admins {
+		v.Admins.SetCell(i+1, 0, tview.NewTableCell(strconv.Itoa(admin.Level)).SetAlign(tview.AlignRight).SetReference(admin))
+		v.Admins.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(admin.Name)))
+		v.Admins.SetCell(i+1, 2, tview.NewTableCell(admin.PlatformID).SetExpansion(1))
+	}
+}
+
+// adminDialog adds an admin, or changes the level of an existing one
+func (v *BansView) adminDialog(admin model.Admin) {
+	title := " Add Admin "
+	if admin.PlatformID != "" {
+		title = " Change Level "
+	}
+
+	form := tview.NewForm().
+		AddInputField("Player", admin.PlatformID, 30, nil, nil).
+		AddInputField("Level", strconv.Itoa(admin.Level), 6, tview.InputFieldInteger, nil).
+		AddInputField("Name", admin.Name, 30, nil, nil)
+
+	form.AddButton("Save", func() {
+		player := strings.TrimSpace(form.GetFormItemByLabel("Player").(*tview.InputField).GetText())
+		level := form.GetFormItemByLabel("Level").(*tview.InputField).GetText()
+		name := form.GetFormItemByLabel("Name").(*tview.InputField).GetText()
+		if player == "" || level == "" {
+			return
+		}
+
+		// "admin add" replaces the level of an existing entry
+		cmd := fmt.Sprintf("admin add %s %s", player, level)
+		if strings.TrimSpace(name) != "" {
+			cmd += " " + quoteArg(name)
+		}
+		v.app.confirm(fmt.Sprintf("Set the permission level of %s to %s?", player, level), func() {
+			v.app.runCommand(cmd, v.app.Collector.RefreshBans)
+		})
+	})
+	form.AddButton("Cancel", v.app.closeModal)
+	form.SetCancelFunc(v.app.closeModal)
+	form.SetBorder(true).SetTitle(title)
+
+	v.app.showModal(form, 50, 11)
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"7dtd-monitor/internal/model"
+	"7dtd-monitor/internal/store"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// LandClaimsView is the "Land Claims" page. Owners who have been away the
+// longest are listed first, so abandoned bases are at the top.
+type LandClaimsView struct {
+	*tview.Flex
+	Table  *tview.Table
+	Detail *tview.TextView
+
+	known *store.KnownPlayers
+}
+
+func NewLandClaimsView(known *store.KnownPlayers) *LandClaimsView {
+	v := &LandClaimsView{
+		Table:  tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		Detail: tview.NewTextView().SetDynamicColors(true),
+		known:  known,
+	}
+	v.Table.SetBorder(true).SetTitle(" Land Claims ")
+	v.Detail.SetBorder(true).SetTitle(" Keystones ")
+	v.Table.SetCell(0, 0, tview.NewTableCell("Waiting for land claims...").SetSelectable(false))
+
+	v.Table.SetSelectionChangedFunc(func(row, column int) {
+		v.showDetail(row)
+	})
+
+	v.Flex = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(v.Table, 0, 3, true).
+		AddItem(v.Detail, 0, 1, false)
+	return v
+}
+
+// Update shows owners. Must run on the UI goroutine.
+func (v *LandClaimsView) Update(owners []model.LandClaimOwner) {
+	// Last online per owner, from the known players store
+	type row struct {
+		owner      model.LandClaimOwner
+		online     bool
+		lastOnline time.Time
+	}
+	rows := make([]row, 0, len(owners))
+	for _, o := range owners {
+		r := row{owner: o}
+		if p, ok := v.known.Get(o.PlatformID); ok {
+			r.online = p.Online
+			r.lastOnline = p.LastOnline
+		}
+		rows = append(rows, r)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].online != rows[j].online {
+			return !rows[i].online
+		}
+		return rows[i].lastOnline.Before(rows[j].lastOnline)
+	})
+
+	selected, _ := v.Table.GetSelection()
+	v.Table.Clear()
+	headers := []string{"Owner", "Platform ID", "Claims", "Status", "Last Online"}
+	for i, h := range headers {
+		v.Table.SetCell(0, i,
+			tview.NewTableCell(h).
+				SetTextColor(tview.Styles.SecondaryTextColor).
+				SetSelectable(false))
+	}
+
+	for i, r := range rows {
+		status := "[green]active[white]"
+		if !r.owner.Active {
+			status = "[red]decayed[white]"
+		}
+		lastOnline := "unknown"
+		switch {
+		case r.online:
+			lastOnline = "[green]online[white]"
+		case !r.lastOnline.IsZero():
+			lastOnline = formatAgo(time.Since(r.lastOnline)) + " ago"
+		}
+
+		nameCell := tview.NewTableCell(tview.Escape(r.owner.Name)).SetReference(r.owner)
+		v.Table.SetCell(i+1, 0, nameCell)
+		v.Table.SetCell(i+1, 1, tview.NewTableCell(r.owner.PlatformID))
+		v.Table.SetCell(i+1, 2, tview.NewTableCell(fmt.Sprintf("%d", len(r.owner.Claims))).SetAlign(tview.AlignRight))
+		v.Table.SetCell(i+1, 3, tview.NewTableCell(status))
+		v.Table.SetCell(i+1, 4, tview.NewTableCell(lastOnline).SetExpansion(1))
+	}
+
+	if selected < 1 || selected > len(rows) {
+		selected = 1
+	}
+	v.Table.Select(selected, 0)
+	v.showDetail(selected)
+}
+
+func (v *LandClaimsView) showDetail(row int) {
+	v.Detail.Clear()
+	if row <= 0 {
+		return
+	}
+	owner, ok := v.Table.GetCell(row, 0).GetReference().(model.LandClaimOwner)
+	if !ok {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, " [yellow]%s[white] (hardiness x%g)\n", tview.Escape(owner.Name), owner.Hardiness)
+	for _, c := range owner.Claims {
+		fmt.Fprintf(&b, " (%.0f, %.0f, %.0f)\n", c.X, c.Y, c.Z)
+	}
+	v.Detail.SetText(b.String())
+}
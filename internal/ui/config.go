@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"7dtd-monitor/internal/model"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// How long a changed setting stays highlighted
+const configHighlight = 10 * time.Minute
+
+var changedColor = tcell.ColorOrange
+
+// ConfigView is the "Server Config" panel. It lists the key settings first,
+// then every raw pref and stat, and highlights values that changed.
+type ConfigView struct {
+	*tview.Table
+
+	prev      map[string]string
+	was       map[string]string    // Previous value of changed settings
+	changedAt map[string]time.Time // When each setting last changed
+}
+
+// Settings shown at the top, by their raw name
+var keySettings = []struct {
+	label string
+	key   string
+}{
+	{"Game Name", "GamePref.GameName"},
+	{"World", "GamePref.GameWorld"},
+	{"Difficulty", "GamePref.GameDifficulty"},
+	{"Max Players", "GamePref.ServerMaxPlayerCount"},
+	{"Day Length (min)", "GamePref.DayNightLength"},
+	{"Daylight (h)", "GamePref.DayLightLength"},
+	{"Blood Moon Every (days)", "GamePref.BloodMoonFrequency"},
+	{"Blood Moon Range", "GamePref.BloodMoonRange"},
+	{"Loot Abundance (%)", "GamePref.LootAbundance"},
+	{"Loot Respawn (days)", "GamePref.LootRespawnDays"},
+	{"Land Claims", "GamePref.LandClaimCount"},
+	{"Land Claim Size", "GamePref.LandClaimSize"},
+	{"Land Claim Dead Zone", "GamePref.LandClaimDeadZone"},
+	{"Land Claim Expiry (days)", "GamePref.LandClaimExpiryTime"},
+	{"Land Claim Decay Mode", "GamePref.LandClaimDecayMode"},
+	{"Claim Durability Online", "GamePref.LandClaimOnlineDurabilityModifier"},
+	{"Claim Durability Offline", "GamePref.LandClaimOfflineDurabilityModifier"},
+}
+
+func NewConfigView() *ConfigView {
+	v := &ConfigView{
+		Table:     tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		was:       make(map[string]string),
+		changedAt: make(map[string]time.Time),
+	}
+	v.SetBorder(true).SetTitle(" Server Config ")
+	v.SetCell(0, 0, tview.NewTableCell("Waiting for game prefs...").SetSelectable(false))
+	return v
+}
+
+// Update shows prefs. Must run on the UI goroutine.
+func (v *ConfigView) Update(prefs model.GamePrefs) {
+	now := time.Now()
+	if v.prev != nil {
+		for key, val := range prefs.Values {
+			if old, ok := v.prev[key]; ok && old != val {
+				v.was[key] = old
+				v.changedAt[key] = now
+			}
+		}
+	}
+	v.prev = prefs.Values
+
+	v.Clear()
+	header := func(col int, text string) {
+		v.SetCell(0, col, tview.NewTableCell(text).
+			SetTextColor(tview.Styles.SecondaryTextColor).
+			SetSelectable(false))
+	}
+	header(0, "Setting")
+	header(1, "Value")
+	header(2, "")
+
+	row := 1
+	add := func(label, key string) {
+		val, ok := prefs.Values[key]
+		if !ok {
+			return
+		}
+		labelCell := tview.NewTableCell(label)
+		valueCell := tview.NewTableCell(tview.Escape(val)).SetExpansion(1)
+		note := ""
+		if at, ok := v.changedAt[key]; ok && now.Sub(at) < configHighlight {
+			labelCell.SetTextColor(changedColor)
+			valueCell.SetTextColor(changedColor)
+			note = fmt.Sprintf("was %s, %s ago", tview.Escape(v.was[key]), now.Sub(at).Round(time.Second))
+		}
+		v.SetCell(row, 0, labelCell)
+		v.SetCell(row, 1, valueCell)
+		v.SetCell(row, 2, tview.NewTableCell(note).SetTextColor(changedColor))
+		row++
+	}
+
+	shown := make(map[string]bool, len(keySettings))
+	for _, s := range keySettings {
+		add(s.label, s.key)
+		shown[s.key] = true
+	}
+
+	// Everything else, prefs before stats, each group after a blank row since
+	// some names like LandClaimCount exist in both
+	keys := make([]string, 0, len(prefs.Values))
+	for key := range prefs.Values {
+		if !shown[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var group string
+	for i, key := range keys {
+		prefix, name, ok := strings.Cut(key, ".")
+		if !ok {
+			prefix, name = "", key
+		}
+		if i == 0 || prefix != group {
+			v.SetCell(row, 0, tview.NewTableCell("").SetSelectable(false))
+			row++
+			group = prefix
+		}
+		add(name, key)
+	}
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"7dtd-monitor/internal/parser"
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// Name of the page dialogs are shown on, above whatever page is current
+const modalPage = "modal"
+
+// showModal overlays p in the middle of the current page and focuses it
+func (a *App) showModal(p tview.Primitive, width, height int) {
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+	a.openModal(centered, p)
+}
+
+// openModal puts page on the modal page and focuses focus
+func (a *App) openModal(page, focus tview.Primitive) {
+	// Replacing an open dialog keeps the focus it will return to
+	if !a.Pages.HasPage(modalPage) {
+		a.modalReturn = a.TviewApp.GetFocus()
+	}
+	a.Pages.AddPage(modalPage, page, true, true)
+	a.TviewApp.SetFocus(focus)
+}
+
+// closeModal removes the dialog and gives focus back
+func (a *App) closeModal() {
+	a.Pages.RemovePage(modalPage)
+	a.detail = nil
+	if a.modalReturn != nil {
+		a.TviewApp.SetFocus(a.modalReturn)
+		a.modalReturn = nil
+	}
+}
+
+// confirm asks a yes/no question and calls onYes if confirmed. Esc cancels.
+func (a *App) confirm(text string, onYes func()) {
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"Cancel", "Confirm"}).
+		SetDoneFunc(func(_ int, label string) {
+			a.closeModal()
+			if label == "Confirm" {
+				onYes()
+			}
+		})
+	// tview.Modal centers itself, so it takes the whole page
+	a.openModal(modal, modal)
+}
+
+// runCommand sends cmd in the background and writes it and its output to the
+// log. after, if set, runs on the same goroutine once the command succeeded.
+func (a *App) runCommand(cmd string, after func()) {
+	a.LogView.Write([]byte(fmt.Sprintf("[yellow]> %s[white]\n", tview.Escape(cmd))))
+	a.LogView.ScrollToEnd()
+
+	go func() {
+		resp, err := a.Client.SendCommand(cmd)
+		if err != nil {
+			a.TviewApp.QueueUpdateDraw(func() {
+				a.LogView.Write([]byte(fmt.Sprintf("[red]Error: %v[white]\n", err)))
+				a.LogView.ScrollToEnd()
+			})
+			return
+		}
+
+		a.TviewApp.QueueUpdateDraw(func() {
+			clean, logs := parser.SplitLogs(resp)
+			for _, l := range logs {
+				a.LogView.Write([]byte(fmt.Sprintf("[gray]%s[white]\n", tview.Escape(l))))
+			}
+			if clean != "" {
+				a.LogView.Write([]byte(tview.Escape(clean) + "\n"))
+			}
+			a.LogView.ScrollToEnd()
+		})
+		if after != nil {
+			after()
+		}
+	}()
+}
+
+// quoteArg quotes a free text command argument. The console has no escaping,
+// so double quotes inside are swapped for single ones.
+func quoteArg(s string) string {
+	return `"` + strings.ReplaceAll(strings.TrimSpace(s), `"`, "'") + `"`
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"7dtd-monitor/internal/model"
+	"7dtd-monitor/internal/store"
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// KnownPlayersView is the "Known Players" page: a search box over the
+// known players store, including players who are offline.
+type KnownPlayersView struct {
+	*tview.Flex
+	Search *tview.InputField
+	Table  *tview.Table
+
+	app   *tview.Application
+	known *store.KnownPlayers
+	// Called with the selected player on 'b' and 'u'
+	onBan, onUnban func(p model.KnownPlayer)
+}
+
+func NewKnownPlayersView(app *tview.Application, known *store.KnownPlayers, onBan, onUnban func(p model.KnownPlayer)) *KnownPlayersView {
+	v := &KnownPlayersView{
+		Search: tview.NewInputField().
+			SetLabel("Search: ").
+			SetFieldWidth(0).
+			SetFieldBackgroundColor(tview.Styles.PrimitiveBackgroundColor),
+		Table:   tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		app:     app,
+		known:   known,
+		onBan:   onBan,
+		onUnban: onUnban,
+	}
+	v.Table.SetBorder(true).SetTitle(" Known Players (/: search, b: ban, u: unban) ")
+
+	v.Search.SetChangedFunc(func(string) { v.Refresh() })
+	// Enter moves from the search box into the results
+	v.Search.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			v.app.SetFocus(v.Table)
+		}
+	})
+
+	v.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		p, ok := v.selected()
+		if !ok {
+			return event
+		}
+		switch event.Rune() {
+		case 'b':
+			v.onBan(p)
+			return nil
+		case 'u':
+			v.onUnban(p)
+			return nil
+		case '/':
+			v.app.SetFocus(v.Search)
+			return nil
+		}
+		return event
+	})
+
+	v.Flex = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(v.Search, 1, 0, false).
+		AddItem(v.Table, 0, 1, true)
+	v.Refresh()
+	return v
+}
+
+func (v *KnownPlayersView) selected() (model.KnownPlayer, bool) {
+	row, _ := v.Table.GetSelection()
+	if row <= 0 {
+		return model.KnownPlayer{}, false
+	}
+	cell := v.Table.GetCell(row, 0)
+	p, ok := cell.GetReference().(model.KnownPlayer)
+	return p, ok
+}
+
+// Refresh re-runs the search. Must run on the UI goroutine.
+func (v *KnownPlayersView) Refresh() {
+	players := v.known.Search(v.Search.GetText())
+
+	v.Table.Clear()
+	headers := []string{"Name", "Platform ID", "Online", "Last Online", "Playtime", "IP"}
+	for i, h := range headers {
+		v.Table.SetCell(0, i,
+			tview.NewTableCell(h).
+				SetTextColor(tview.Styles.SecondaryTextColor).
+				SetSelectable(false))
+	}
+
+	for i, p := range players {
+		row := i + 1
+		online := "no"
+		lastOnline := "never"
+		if p.Online {
+			online = "[green]yes[white]"
+			lastOnline = "now"
+		} else if !p.LastOnline.IsZero() {
+			lastOnline = fmt.Sprintf("%s (%s ago)", p.LastOnline.Format("2006-01-02 15:04"), formatAgo(time.Since(p.LastOnline)))
+		}
+
+		nameCell := tview.NewTableCell(tview.Escape(p.Name)).SetReference(p)
+		v.Table.SetCell(row, 0, nameCell)
+		v.Table.SetCell(row, 1, tview.NewTableCell(p.PlatformID))
+		v.Table.SetCell(row, 2, tview.NewTableCell(online))
+		v.Table.SetCell(row, 3, tview.NewTableCell(lastOnline))
+		v.Table.SetCell(row, 4, tview.NewTableCell(formatAgo(time.Duration(p.Playtime)*time.Second)).SetAlign(tview.AlignRight))
+		v.Table.SetCell(row, 5, tview.NewTableCell(p.IP).SetExpansion(1))
+	}
+}
+
+// formatAgo prints coarse durations: "3d 4h", "5h 12m", "42m"
+func formatAgo(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
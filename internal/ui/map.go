@@ -0,0 +1,283 @@
+package ui
+
+import (
+	"7dtd-monitor/internal/model"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Blocks per map cell, from most to least zoomed in
+var mapScales = []float64{1, 2, 4, 8, 16, 32, 64, 128}
+
+const (
+	defaultMapScale = 2        // Index into mapScales
+	hordeRadius     = 40.0     // Blocks; zombies this close to a player count as near
+	claimRadius     = 100.0    // Blocks; foreign keystones this close are listed
+	mapCellAspect   = 2.0      // Terminal cells are about twice as tall as wide
+	mapPanCells     = 5        // Cells moved per arrow key
+	noPlayer        = "<none>" // Shown when nobody is online
+)
+
+// Horde shading by zombies per cell
+var densityRunes = []rune{'•', '░', '▒', '▓', '█'}
+
+// MapView is a top-down map of the world: players as labeled markers,
+// zombies and animals as dots, land claims as squares. North is up.
+type MapView struct {
+	*tview.Box
+
+	players  []model.Player
+	entities []model.Entity
+	claims   []model.LandClaimOwner
+
+	centerX, centerZ float64
+	centered         bool // False until the first data arrives
+	scale            int  // Index into mapScales
+	selected         int  // Index into players
+	follow           bool // Keep the selected player in the middle
+}
+
+func NewMapView() *MapView {
+	m := &MapView{
+		Box:   tview.NewBox(),
+		scale: defaultMapScale,
+	}
+	m.SetBorder(true).SetTitle(" Map (+/-: zoom, arrows: pan, n/p: select player, c: center) ")
+	return m
+}
+
+// SetPositions updates players and entities. Must run on the UI goroutine.
+func (m *MapView) SetPositions(players []model.Player, entities []model.Entity) {
+	var current string
+	if m.selected < len(m.players) {
+		current = m.players[m.selected].PlatformID
+	}
+
+	m.players = players
+	m.entities = entities
+	m.selected = 0
+	for i, p := range players {
+		if p.PlatformID == current {
+			m.selected = i
+		}
+	}
+
+	switch {
+	case m.follow && m.selected < len(players):
+		m.centerOn(players[m.selected].Position)
+	case !m.centered && len(players) > 0:
+		// Start in the middle of everyone
+		var sum model.Vec3
+		for _, p := range players {
+			sum.X += p.Position.X
+			sum.Z += p.Position.Z
+		}
+		m.centerOn(model.Vec3{X: sum.X / float64(len(players)), Z: sum.Z / float64(len(players))})
+	}
+}
+
+// SetClaims updates the land claims shown. Must run on the UI goroutine.
+func (m *MapView) SetClaims(claims []model.LandClaimOwner) {
+	m.claims = claims
+}
+
+// Follow selects a player by platform ID and keeps them centered
+func (m *MapView) Follow(platformID string) {
+	for i, p := range m.players {
+		if p.PlatformID == platformID {
+			m.selected = i
+			m.follow = true
+			m.centerOn(p.Position)
+		}
+	}
+}
+
+func (m *MapView) centerOn(pos model.Vec3) {
+	m.centerX, m.centerZ = pos.X, pos.Z
+	m.centered = true
+}
+
+// Selected returns the name of the selected player
+func (m *MapView) Selected() string {
+	if m.selected < len(m.players) {
+		return m.players[m.selected].Name
+	}
+	return noPlayer
+}
+
+// Draw implements tview.Primitive
+func (m *MapView) Draw(screen tcell.Screen) {
+	m.DrawForSubclass(screen, m)
+	x, y, width, height := m.GetInnerRect()
+	height-- // Status line
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	scale := mapScales[m.scale]
+	toCell := func(pos model.Vec3) (int, int, bool) {
+		cx := x + width/2 + int(math.Floor((pos.X-m.centerX)/scale))
+		cy := y + height/2 - int(math.Floor((pos.Z-m.centerZ)/(scale*mapCellAspect)))
+		return cx, cy, cx >= x && cx < x+width && cy >= y && cy < y+height
+	}
+	style := tcell.StyleDefault.Background(tview.Styles.PrimitiveBackgroundColor)
+
+	// Land claims
+	for _, owner := range m.claims {
+		for _, c := range owner.Claims {
+			if cx, cy, ok := toCell(c); ok {
+				screen.SetContent(cx, cy, '■', nil, style.Foreground(tcell.ColorBlue))
+			}
+		}
+	}
+
+	// Zombies are counted per cell so hordes show up as denser shading
+	type cell struct{ x, y int }
+	zombies := make(map[cell]int)
+	for _, e := range m.entities {
+		if e.Dead {
+			continue
+		}
+		cx, cy, ok := toCell(e.Position)
+		if !ok {
+			continue
+		}
+		switch e.Category {
+		case model.CategoryZombie:
+			zombies[cell{cx, cy}]++
+		case model.CategoryAnimal:
+			screen.SetContent(cx, cy, '•', nil, style.Foreground(tcell.ColorGreen))
+		}
+	}
+	for c, n := range zombies {
+		screen.SetContent(c.x, c.y, densityRunes[min(n, len(densityRunes))-1], nil, style.Foreground(tcell.ColorRed))
+	}
+
+	// Players last so they are never hidden, and labels before markers so a
+	// long name can't cover someone standing next to them
+	playerColor := func(i int) tcell.Color {
+		if i == m.selected {
+			return tcell.ColorFuchsia
+		}
+		return tcell.ColorYellow
+	}
+	for i, p := range m.players {
+		if cx, cy, ok := toCell(p.Position); ok {
+			tview.Print(screen, tview.Escape(p.Name), cx+1, cy, x+width-cx-1, tview.AlignLeft, playerColor(i))
+		}
+	}
+	for i, p := range m.players {
+		if cx, cy, ok := toCell(p.Position); ok {
+			screen.SetContent(cx, cy, '@', nil, style.Foreground(playerColor(i)))
+		}
+	}
+
+	status := fmt.Sprintf("[gray]center (%.0f, %.0f)  1 cell = %g blocks  selected: %s",
+		m.centerX, m.centerZ, scale, tview.Escape(m.Selected()))
+	if m.follow {
+		status += " (following)"
+	}
+	tview.Print(screen, status, x, y+height, width, tview.AlignLeft, tcell.ColorGray)
+}
+
+// InputHandler implements tview.Primitive
+func (m *MapView) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return m.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		step := mapScales[m.scale] * mapPanCells
+		pan := func(dx, dz float64) {
+			m.centerX += dx
+			m.centerZ += dz
+			m.follow = false
+		}
+
+		switch event.Key() {
+		case tcell.KeyUp:
+			pan(0, step*mapCellAspect)
+		case tcell.KeyDown:
+			pan(0, -step*mapCellAspect)
+		case tcell.KeyLeft:
+			pan(-step, 0)
+		case tcell.KeyRight:
+			pan(step, 0)
+		}
+
+		switch event.Rune() {
+		case '+', '=':
+			m.scale = max(m.scale-1, 0)
+		case '-':
+			m.scale = min(m.scale+1, len(mapScales)-1)
+		case 'n', 'p':
+			if len(m.players) == 0 {
+				return
+			}
+			if event.Rune() == 'n' {
+				m.selected = (m.selected + 1) % len(m.players)
+			} else {
+				m.selected = (m.selected + len(m.players) - 1) % len(m.players)
+			}
+			if m.follow {
+				m.centerOn(m.players[m.selected].Position)
+			}
+		case 'c':
+			if m.selected < len(m.players) {
+				m.follow = true
+				m.centerOn(m.players[m.selected].Position)
+			}
+		}
+	})
+}
+
+// Proximity lists, for each player, the zombies around them and how far
+// they are from other players' bases.
+func (m *MapView) Proximity() string {
+	var b strings.Builder
+	for _, p := range m.players {
+		near := 0
+		for _, e := range m.entities {
+			if e.Category == model.CategoryZombie && !e.Dead && distance2D(p.Position, e.Position) <= hordeRadius {
+				near++
+			}
+		}
+
+		fmt.Fprintf(&b, " [yellow]%s[white]\n", tview.Escape(p.Name))
+		if near > 0 {
+			fmt.Fprintf(&b, "   [red]%d zombies[white] within %.0fm\n", near, hordeRadius)
+		}
+
+		type foreign struct {
+			owner string
+			dist  float64
+		}
+		var claims []foreign
+		for _, owner := range m.claims {
+			if owner.PlatformID == p.PlatformID {
+				continue
+			}
+			closest := math.Inf(1)
+			for _, c := range owner.Claims {
+				closest = min(closest, distance2D(p.Position, c))
+			}
+			if closest <= claimRadius {
+				claims = append(claims, foreign{owner.Name, closest})
+			}
+		}
+		sort.Slice(claims, func(i, j int) bool { return claims[i].dist < claims[j].dist })
+		for _, c := range claims {
+			fmt.Fprintf(&b, "   [blue]%.0fm[white] from %s's base\n", c.dist, tview.Escape(c.owner))
+		}
+		if near == 0 && len(claims) == 0 {
+			b.WriteString("   [gray]nothing nearby[white]\n")
+		}
+	}
+	return b.String()
+}
+
+// distance2D ignores height, which is what matters on a top-down map
+func distance2D(a, b model.Vec3) float64 {
+	return math.Hypot(a.X-b.X, a.Z-b.Z)
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"7dtd-monitor/internal/collector"
+	"7dtd-monitor/internal/metrics"
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Time ranges the Metrics page steps through, up to the history kept
+var metricsSteps = []time.Duration{5 * time.Minute, 15 * time.Minute, 30 * time.Minute, time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour}
+
+// metricsWindows returns the steps shorter than history, then history itself
+func metricsWindows(history time.Duration) []time.Duration {
+	var windows []time.Duration
+	for _, w := range metricsSteps {
+		if w < history {
+			windows = append(windows, w)
+		}
+	}
+	return append(windows, history)
+}
+
+// A metric drawn on the Metrics page
+type metricSeries struct {
+	label string
+	unit  string
+	color tcell.Color
+	value func(metrics.Sample) float64
+}
+
+var metricSeriesList = []metricSeries{
+	{"FPS", "", tcell.ColorGreen, func(s metrics.Sample) float64 { return s.FPS }},
+	{"Heap", "MB", tcell.ColorBlue, func(s metrics.Sample) float64 { return s.HeapMB }},
+	{"RSS", "MB", tcell.ColorBlue, func(s metrics.Sample) float64 { return s.RSSMB }},
+	{"Chunks", "", tcell.ColorTeal, func(s metrics.Sample) float64 { return float64(s.Chunks) }},
+	{"Players", "", tcell.ColorYellow, func(s metrics.Sample) float64 { return float64(s.Players) }},
+	{"Zombies", "", tcell.ColorRed, func(s metrics.Sample) float64 { return float64(s.Zombies) }},
+	{"Avg Ping", "ms", tcell.ColorFuchsia, func(s metrics.Sample) float64 { return float64(s.AvgPing) }},
+}
+
+// MetricsView is the "Metrics" page: a sparkline per metric over the chosen
+// time window, with the range and the change over the window.
+type MetricsView struct {
+	*tview.Box
+
+	ring    *metrics.Ring
+	windows []time.Duration
+	window  int // Index into windows
+}
+
+func NewMetricsView(ring *metrics.Ring) *MetricsView {
+	windows := metricsWindows(time.Duration(ring.Size()) * collector.StatsInterval)
+	v := &MetricsView{
+		Box:     tview.NewBox(),
+		ring:    ring,
+		windows: windows,
+		window:  min(1, len(windows)-1),
+	}
+	v.SetBorder(true)
+	v.updateTitle()
+	return v
+}
+
+func (v *MetricsView) updateTitle() {
+	v.SetTitle(fmt.Sprintf(" Metrics, last %s (+/-: time range) ", formatAgo(v.windows[v.window])))
+}
+
+// Draw implements tview.Primitive
+func (v *MetricsView) Draw(screen tcell.Screen) {
+	v.DrawForSubclass(screen, v)
+	x, y, width, height := v.GetInnerRect()
+
+	samples := v.ring.Since(time.Now().Add(-v.windows[v.window]))
+	if len(samples) == 0 {
+		tview.Print(screen, "Waiting for samples...", x+1, y, width-1, tview.AlignLeft, tcell.ColorGray)
+		return
+	}
+
+	// Each metric gets a header line, a sparkline and a blank line
+	row := y
+	for _, m := range metricSeriesList {
+		if row+1 >= y+height {
+			break
+		}
+		values := make([]float64, len(samples))
+		lo, hi := m.value(samples[0]), m.value(samples[0])
+		for i, s := range samples {
+			values[i] = m.value(s)
+			lo = min(lo, values[i])
+			hi = max(hi, values[i])
+		}
+		first, last := values[0], values[len(values)-1]
+
+		header := fmt.Sprintf("[yellow]%s[white] %s%s  [gray]min %s  max %s  change %+.1f%s",
+			m.label, formatMetric(last), m.unit, formatMetric(lo), formatMetric(hi), last-first, m.unit)
+		tview.Print(screen, header, x+1, row, width-1, tview.AlignLeft, tcell.ColorWhite)
+		tview.Print(screen, sparkline(resample(values, width-2)), x+1, row+1, width-2, tview.AlignLeft, m.color)
+		row += 3
+	}
+}
+
+// InputHandler implements tview.Primitive
+func (v *MetricsView) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return v.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		switch event.Rune() {
+		case '+', '=':
+			v.window = min(v.window+1, len(v.windows)-1)
+		case '-':
+			v.window = max(v.window-1, 0)
+		}
+		v.updateTitle()
+	})
+}
+
+// resample averages values into at most width buckets
+func resample(values []float64, width int) []float64 {
+	if width <= 0 || len(values) <= width {
+		return values
+	}
+	out := make([]float64, width)
+	for i := range out {
+		start, end := i*len(values)/width, (i+1)*len(values)/width
+		var sum float64
+		for _, v := range values[start:end] {
+			sum += v
+		}
+		out[i] = sum / float64(end-start)
+	}
+	return out
+}
+
+// formatMetric drops the decimals of whole numbers
+func formatMetric(v float64) string {
+	if v == float64(int64(v)) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.1f", v)
+}
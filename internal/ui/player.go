@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"7dtd-monitor/internal/events"
+	"7dtd-monitor/internal/model"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Lines of each history section shown in the detail pane
+const detailHistoryLines = 8
+
+// PlayerDetail is the pane opened with Enter on an online player. It shows
+// everything the monitor knows about them and offers the player actions.
+type PlayerDetail struct {
+	*tview.TextView
+
+	app    *App
+	player model.Player
+}
+
+func NewPlayerDetail(app *App, p model.Player) *PlayerDetail {
+	d := &PlayerDetail{
+		TextView: tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
+		app:      app,
+		player:   p,
+	}
+	d.SetBorder(true).SetTitle(" Player (k: kick, b: ban, t: teleport, g: give item, u: buff, Esc: close) ")
+
+	d.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			d.app.closeModal()
+			return nil
+		}
+		target := playerTarget{ID: d.player.ID, Name: d.player.Name, PlatformID: d.player.PlatformID, IP: d.player.IP}
+		switch event.Rune() {
+		case 'k':
+			d.app.kickDialog(target)
+		case 'b':
+			d.app.banDialog(target)
+		case 't':
+			d.app.teleportDialog(target)
+		case 'g':
+			d.giveDialog()
+		case 'u':
+			d.buffDialog()
+		default:
+			return event
+		}
+		return nil
+	})
+
+	d.Refresh(p)
+	return d
+}
+
+// Refresh redraws the pane with freshly polled player data. Must run on the UI goroutine.
+func (d *PlayerDetail) Refresh(p model.Player) {
+	d.player = p
+	history := d.app.History.Get(p.PlatformID)
+	known, _ := d.app.Known.Get(p.PlatformID)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, " [yellow]%s[white] (entity %s)\n", tview.Escape(p.Name), p.ID)
+	fmt.Fprintf(&b, " Platform ID: %s  Crossplatform ID: %s\n", p.PlatformID, p.CrossplatformID)
+	fmt.Fprintf(&b, " IP: %s\n\n", p.IP)
+
+	fmt.Fprintf(&b, " [green]Position:[white] (%.0f, %.0f, %.0f)  [green]Health:[white] %d  [green]Level:[white] %d  [green]Score:[white] %d\n",
+		p.Position.X, p.Position.Y, p.Position.Z, p.Health, p.Level, p.Score)
+	session := "unknown"
+	if !history.SessionStart.IsZero() {
+		session = formatAgo(time.Since(history.SessionStart))
+	}
+	fmt.Fprintf(&b, " [green]Session:[white] %s  [green]Total playtime:[white] %s\n", session, formatAgo(time.Duration(known.Playtime)*time.Second))
+	fmt.Fprintf(&b, " [green]Zombie kills:[white] %d  [green]Player kills:[white] %d  [green]Deaths:[white] %d\n\n",
+		p.Zombies, p.PlayerKills, p.Deaths)
+
+	pings := make([]float64, len(history.Pings))
+	lo, hi := 0, 0
+	for i, ping := range history.Pings {
+		pings[i] = float64(ping)
+		if i == 0 || ping < lo {
+			lo = ping
+		}
+		hi = max(hi, ping)
+	}
+	fmt.Fprintf(&b, " [green]Ping:[white] %d ms  [blue]%s[white]  (%d-%d ms)\n", p.Ping, sparkline(pings), lo, hi)
+
+	ips := known.IPs
+	if len(ips) == 0 {
+		ips = []string{p.IP}
+	}
+	fmt.Fprintf(&b, " [green]IP history:[white] %s\n", strings.Join(ips, ", "))
+
+	var combat, sanctions, chat []string
+	for _, ev := range history.Events {
+		at := ev.Time.Format("01-02 15:04")
+		switch ev.Type {
+		case events.PlayerDied:
+			combat = append(combat, fmt.Sprintf("%s died", at))
+		case events.PlayerKilledByPlayer:
+			if ev.Killer.Name == p.Name {
+				combat = append(combat, fmt.Sprintf("%s killed %s", at, tview.Escape(ev.Player.Name)))
+			} else {
+				combat = append(combat, fmt.Sprintf("%s killed by %s", at, tview.Escape(ev.Killer.Name)))
+			}
+		case events.Kick:
+			sanctions = append(sanctions, fmt.Sprintf("%s kicked: %s", at, tview.Escape(ev.Message)))
+		case events.Ban:
+			sanctions = append(sanctions, fmt.Sprintf("%s banned until %s: %s", at, ev.Until, tview.Escape(ev.Message)))
+		case events.ChatMessage:
+			chat = append(chat, fmt.Sprintf("%s %s %s", at, tview.Escape("["+ev.Channel+"]"), tview.Escape(ev.Message)))
+		}
+	}
+	// Bans from before the monitor started
+	for _, ban := range d.app.bans {
+		if ban.PlatformID == p.PlatformID {
+			sanctions = append(sanctions, fmt.Sprintf("on ban list until %s: %s", ban.UntilRaw, tview.Escape(ban.Reason)))
+		}
+	}
+
+	section := func(title string, lines []string) {
+		fmt.Fprintf(&b, "\n [yellow]%s[white]\n", title)
+		if len(lines) == 0 {
+			b.WriteString("   none seen\n")
+		}
+		if len(lines) > detailHistoryLines {
+			lines = lines[len(lines)-detailHistoryLines:]
+		}
+		for _, l := range lines {
+			fmt.Fprintf(&b, "   %s\n", l)
+		}
+	}
+	section("Kills & Deaths", combat)
+	section("Kicks & Bans", sanctions)
+	section("Recent Chat", chat)
+
+	d.SetText(b.String())
+}
+
+// teleportDialog moves t to coordinates or to another player
+func (a *App) teleportDialog(t playerTarget) {
+	dest := tview.NewInputField().SetLabel("Destination").SetFieldWidth(30).
+		SetPlaceholder("x y z, or a player name")
+	a.commandDialog(" Teleport ", "Teleport", func() string {
+		to := strings.TrimSpace(dest.GetText())
+		if to == "" {
+			return ""
+		}
+		if strings.Count(to, " ") != 2 {
+			to = quoteArg(to)
+		}
+		return fmt.Sprintf("teleportplayer %s %s", t.ID, to)
+	}, dest)
+}
+
+// giveDialog drops an item at the player's feet
+func (d *PlayerDetail) giveDialog() {
+	item := tview.NewInputField().SetLabel("Item").SetFieldWidth(30)
+	count := tview.NewInputField().SetLabel("Amount").SetFieldWidth(6).SetText("1").
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	quality := tview.NewInputField().SetLabel("Quality").SetFieldWidth(6).
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	d.app.commandDialog(" Give Item ", "Give", func() string {
+		name := strings.TrimSpace(item.GetText())
+		if name == "" || count.GetText() == "" {
+			return ""
+		}
+		cmd := fmt.Sprintf("give %s %s %s", d.player.ID, name, count.GetText())
+		if quality.GetText() != "" {
+			cmd += " " + quality.GetText()
+		}
+		return cmd
+	}, item, count, quality)
+}
+
+// buffDialog applies a buff, e.g. buffHealing
+func (d *PlayerDetail) buffDialog() {
+	buff := tview.NewInputField().SetLabel("Buff").SetFieldWidth(30)
+	d.app.commandDialog(" Buff ", "Apply", func() string {
+		name := strings.TrimSpace(buff.GetText())
+		if name == "" {
+			return ""
+		}
+		return fmt.Sprintf("buffplayer %s %s", d.player.ID, name)
+	}, buff)
+}
+
+// commandDialog shows a form of fields and runs the command build returns
+// when button is pressed. An empty command keeps the dialog open.
+func (a *App) commandDialog(title, button string, build func() string, fields ...tview.FormItem) {
+	form := tview.NewForm()
+	for _, f := range fields {
+		form.AddFormItem(f)
+	}
+	form.AddButton(button, func() {
+		cmd := build()
+		if cmd == "" {
+			return
+		}
+		a.closeModal()
+		a.runCommand(cmd, nil)
+	})
+	form.AddButton("Cancel", a.closeModal)
+	form.SetCancelFunc(a.closeModal)
+	form.SetBorder(true).SetTitle(title)
+
+	a.showModal(form, 50, 5+2*form.GetFormItemCount())
+}
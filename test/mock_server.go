@@ -2,12 +2,28 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"net"
 	"strings"
+	"sync"
+	"time"
 )
 
+var (
+	started  = time.Now()
+	password = flag.String("password", "", "Require this telnet password (empty accepts anything)")
+)
+
+// logLine formats a message the way 7DTD prefixes its log output
+func logLine(level, msg string) string {
+	now := time.Now()
+	return fmt.Sprintf("%s %.3f %s %s\r\n", now.Format("2006-01-02T15:04:05"), now.Sub(started).Seconds(), level, msg)
+}
+
 func main() {
+	flag.Parse()
+
 	listener, err := net.Listen("tcp", "localhost:8081")
 	if err != nil {
 		fmt.Println("Error starting mock server:", err)
@@ -35,54 +51,248 @@ func handleConnection(conn net.Conn) {
 	writer.WriteString("Please enter password:\r\n")
 	writer.Flush()
 
-	// Expect password (we'll accept anything for mock)
-	if scanner.Scan() {
-		_ = scanner.Text() // consume password
-		writer.WriteString("Logon successful.\r\n\r\n")
+	// Expect password (anything goes unless -password is set)
+	for {
+		if !scanner.Scan() {
+			return
+		}
+		if *password == "" || scanner.Text() == *password {
+			break
+		}
+		writer.WriteString("Password incorrect, please enter password:\r\n")
 		writer.Flush()
 	}
+	writer.WriteString("Logon successful.\r\n\r\n")
+	writer.Flush()
+
+	// The real server streams its log to every telnet session
+	var mu sync.Mutex
+	done := make(chan struct{})
+	defer close(done)
+	go broadcastEvents(writer, &mu, done)
 
 	for {
 		if !scanner.Scan() {
 			return
 		}
+		mu.Lock()
 		cmd := strings.TrimSpace(scanner.Text())
 		response := ""
 
+		// The real server echoes every command into the log before its output
+		writer.WriteString(logLine("INF", fmt.Sprintf("Executing command '%s' by Telnet from %s", cmd, conn.RemoteAddr())))
+
 		switch cmd {
-		case "lpi":
-			response = `Total of 3 in the game
-1. id=171, Survivor (PL), pos=(-1050.5, 65.0, 890.3), rot=(0.0, -135.0, 0.0), remote=True, health=150, deaths=2, zombies=0, players=0, score=15, level=13, steamid=76561198012345678, ip=127.0.0.1, ping=24
-2. id=172, ZombieSlayer, pos=(-1040.1, 65.0, 895.1), rot=(0.0, 45.0, 0.0), remote=True, health=80, deaths=5, zombies=12, players=1, score=55, level=24, steamid=76561198087654321, ip=192.168.0.5, ping=45
-3. id=175, Newbie, pos=(-1060.0, 64.0, 880.0), rot=(0.0, 0.0, 0.0), remote=True, health=100, deaths=0, zombies=0, players=0, score=0, level=1, steamid=76561199000000001, ip=10.0.0.99, ping=120
+		case "lp", "listplayers":
+			response = `1. id=171, Survivor, the (PL), pos=(-1050.5, 65.0, 890.3), rot=(0.0, -135.0, 0.0), remote=True, health=150, deaths=2, zombies=0, players=0, score=15, level=13, pltfmid=Steam_76561198012345678, crossid=EOS_0002aaaaaaaa, ip=127.0.0.1, ping=24
+2. id=172, ZombieSlayer, pos=(-1040.1, 65.0, 895.1), rot=(0.0, 45.0, 0.0), remote=True, health=80, deaths=5, zombies=12, players=1, score=55, level=24, pltfmid=Steam_76561198087654321, crossid=EOS_0002bbbbbbbb, ip=192.168.0.5, ping=45
+3. id=175, Newbie, pos=(-1060.0, 64.0, 880.0), rot=(0.0, 0.0, 0.0), remote=True, health=100, deaths=0, zombies=0, players=0, score=0, level=1, pltfmid=Steam_76561199000000001, crossid=EOS_0002cccccccc, ip=10.0.0.99, ping=120
+Total of 3 in the game
 `
-		case "mem":
-			// Output simulates 7DTD memory output often containing FPS
-			response = `Heap: 2500.5 MB, Max: 3500.0 MB, Objects: 150000
-GC: 120.5 MB
-FPS: 58.4
+		case "lpi", "listplayerids":
+			response = `1. id=171, Survivor, the (PL)
+2. id=172, ZombieSlayer
+3. id=175, Newbie
+Total of 3 in the game
 `
+		case "mem":
+			// Output simulates the single stats line 7DTD prints for mem
+			response = fmt.Sprintf("Time: %.2fm FPS: 58.40 Heap: 2500.5MB Max: 3500.0MB Chunks: 249 CGO: 23 Ply: 3 Zom: 2 Ent: 5 (7) Items: 0 CO: 3 RSS: 4924.3MB\n",
+				time.Since(started).Minutes())
 		case "le":
-			response = `Total of 5 entities in the game
-1. id=171, name=Survivor (PL), ... type=Player
-2. id=801, name=Zombie Boe, ... type=Zombie
-3. id=802, name=Zombie Joe, ... type=Zombie
-4. id=803, name=Stag, ... type=Animal
-5. id=804, name=Bear, ... type=Animal
+			response = `1. id=171, [type=EntityPlayer, name=Survivor, the (PL), id=171], pos=(-1050.5, 65.0, 890.3), rot=(0.0, -135.0, 0.0), lifetime=float.Max, remote=True, dead=False, health=150
+2. id=801, [type=EntityZombie, name=zombieBoe, id=801], pos=(-1030.2, 65.0, 880.7), rot=(0.0, 12.5, 0.0), lifetime=float.Max, remote=False, dead=False, health=120
+3. id=802, [type=EntityZombie, name=zombieJoe, id=802], pos=(-1028.9, 65.0, 882.1), rot=(0.0, 190.0, 0.0), lifetime=float.Max, remote=False, dead=False, health=95
+4. id=803, [type=EntityAnimalStag, name=animalStag, id=803], pos=(-980.0, 70.2, 930.4), rot=(0.0, 88.0, 0.0), lifetime=float.Max, remote=False, dead=False, health=80
+5. id=804, [type=EntityEnemyAnimal, name=animalBear, id=804], pos=(-1100.3, 66.1, 850.0), rot=(0.0, 270.0, 0.0), lifetime=float.Max, remote=False, dead=False, health=300
+6. id=805, [type=EntityAnimalRabbit, name=animalChicken, id=805], pos=(-1045.0, 65.0, 895.0), rot=(0.0, 41.3, 0.0), lifetime=float.Max, remote=False, dead=False, health=10
+7. id=806, [type=EntitySupplyCrate, name=sc_General, id=806], pos=(-1020.0, 120.0, 870.0), rot=(0.0, 0.0, 0.0), lifetime=float.Max, remote=False, dead=False, health=0
+Total of 7 in the game
 `
 		case "gettime":
-			response = `Day 7, 21:45
+			// Starts at Day 7, 21:45 and runs at the default 60 real minutes per day
+			minutes := 7*24*60 + 21*60 + 45 + int(time.Since(started)/(2500*time.Millisecond))
+			response = fmt.Sprintf("Day %d, %02d:%02d\n", minutes/(24*60), minutes/60%24, minutes%60)
+		case "lkp", "listknownplayers":
+			response = `1. Survivor, the (PL), id=171, pltfmid=Steam_76561198012345678, crossid=EOS_0002aaaaaaaa, online=True, ip=127.0.0.1, playtime=1834 m, seen=2025-12-10 10:35
+2. ZombieSlayer, id=172, pltfmid=Steam_76561198087654321, crossid=EOS_0002bbbbbbbb, online=True, ip=192.168.0.5, playtime=9120 m, seen=2025-12-10 10:35
+3. Newbie, id=175, pltfmid=Steam_76561199000000001, crossid=EOS_0002cccccccc, online=True, ip=10.0.0.99, playtime=45 m, seen=2025-12-10 10:35
+4. LateJoiner, id=176, pltfmid=Steam_76561199000000002, crossid=EOS_0002abcdef, online=False, ip=10.0.0.42, playtime=310 m, seen=2025-12-09 21:02
+5. OldTimer, id=98, pltfmid=Steam_76561197960000000, crossid=EOS_0002dddddddd, online=False, ip=172.16.4.20, playtime=24012 m, seen=2025-10-02 18:47
+Total of 5 known
+`
+		case "llp", "listlandclaims":
+			response = `Player "Survivor, the (PL) (Steam_76561198012345678)" owns 1 keystones (protected: True, current hardiness multiplier: 4)
+   (-1052, 65, 888)
+Player "OldTimer (Steam_76561197960000000)" owns 3 keystones (protected: False, current hardiness multiplier: 1)
+   (2310, 44, -1804)
+   (2350, 44, -1790)
+   (2290, 46, -1830)
+Player "LateJoiner (Steam_76561199000000002)" owns 1 keystones (protected: True, current hardiness multiplier: 4)
+   (-980, 70, 930)
+Total of 5 keystones in the game
+`
+		case "ggp", "getgamepref":
+			response = `GamePref.BloodMoonEnemyCount = 8
+GamePref.BloodMoonFrequency = 7
+GamePref.BloodMoonRange = 0
+GamePref.DayLightLength = 18
+GamePref.DayNightLength = 60
+GamePref.GameDifficulty = 2
+GamePref.GameName = MyGame
+GamePref.GameWorld = Navezgane
+GamePref.LandClaimCount = 5
+GamePref.LandClaimDeadZone = 30
+GamePref.LandClaimDecayMode = 0
+GamePref.LandClaimExpiryTime = 7
+GamePref.LandClaimOfflineDurabilityModifier = 4
+GamePref.LandClaimOnlineDurabilityModifier = 4
+GamePref.LandClaimSize = 41
+GamePref.LootAbundance = 100
+GamePref.LootRespawnDays = 7
+GamePref.MaxSpawnedZombies = 64
+GamePref.ServerMaxPlayerCount = 8
+GamePref.ServerName = Mock 7DTD Server
 `
+		case "ggs", "getgamestat":
+			response = `GameStat.BloodMoonDay = 7
+GameStat.DayLimitThisTurn = 0
+GameStat.LandClaimCount = 5
+GameStat.LandClaimSize = 41
+GameStat.ShowFriendPlayerOnMap = True
+`
+		case "ban list":
+			response = banList()
+		case "admin list":
+			response = adminList()
 		case "exit", "quit":
 			writer.WriteString("Goodbye.\r\n")
 			writer.Flush()
+			mu.Unlock()
 			return
 		default:
-			response = fmt.Sprintf("*** Unknown command: %s\r\n", cmd)
+			switch {
+			case strings.HasPrefix(cmd, "ban "):
+				response = banCommand(cmd)
+			case strings.HasPrefix(cmd, "admin "):
+				response = adminCommand(cmd)
+			case strings.HasPrefix(cmd, "say "):
+				// Server chat shows up in the log like player chat
+				msg := strings.Trim(strings.TrimPrefix(cmd, "say "), `"`)
+				response = logLine("INF", fmt.Sprintf("Chat (from '-non-player-', entity id '-1', to 'Global'): 'Server': %s", msg))
+			case strings.HasPrefix(cmd, "kick "):
+				response = fmt.Sprintf("Kicking player %s\n", strings.Fields(cmd)[1])
+			default:
+				response = fmt.Sprintf("*** Unknown command: %s\r\n", cmd)
+			}
 		}
 
 		writer.WriteString(response)
 		writer.WriteString("\r\n") // Prompt spacing
 		writer.Flush()
+		mu.Unlock()
+	}
+}
+
+// Sample game events, replayed in a loop
+var sampleEvents = []string{
+	"Player connected, entityid=176, name=LateJoiner, pltfmid=Steam_76561199000000002, crossid=EOS_0002abcdef, steamOwner=Steam_76561199000000002, ip=10.0.0.42",
+	"PlayerSpawnedInWorld (reason: JoinMultiplayer, position: -1045, 65, 885): EntityID=176, PltfmId='Steam_76561199000000002', CrossId='EOS_0002abcdef', OwnerID='Steam_76561199000000002', PlayerName='LateJoiner', ClientNumber='4'",
+	"Chat (from 'Steam_76561198012345678', entity id '171', to 'Global'): 'Survivor, the (PL)': anyone got a spare wrench?",
+	"Chat (from 'Steam_76561198087654321', entity id '172', to 'Global'): 'ZombieSlayer': check the trader",
+	"GMSG: Player 'Newbie' died",
+	"GMSG: Player 'Newbie' killed by 'ZombieSlayer'",
+	"AIAirDrop: Spawned supply crate at (-1020.0, 180.0, 870.0), plane is at (-900.0, 200.0, 870.0)",
+	"Player disconnected: EntityID=176, PltfmId='Steam_76561199000000002', CrossId='EOS_0002abcdef', OwnerID='Steam_76561199000000002', PlayerName='LateJoiner', ClientNumber='4'",
+}
+
+func broadcastEvents(writer *bufio.Writer, mu *sync.Mutex, done chan struct{}) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+		mu.Lock()
+		writer.WriteString(logLine("INF", sampleEvents[i%len(sampleEvents)]))
+		writer.Flush()
+		mu.Unlock()
+	}
+}
+
+// Bans and admins are shared by all connections so changes show up in the lists
+var (
+	listsMu sync.Mutex
+	bans    = []string{
+		"2035-12-10 10:35:08 - Steam_76561197960000001 (Griefer) - griefing",
+		"2025-12-24 18:00:00 - Steam_76561197960000002 (Spammer) - chat spam",
+	}
+	admins = map[string]string{
+		"Steam_76561198012345678": "0: Steam_76561198012345678 (Survivor, the (PL), Survivor, the (PL))",
+		"Steam_76561198087654321": "1: Steam_76561198087654321 (ZombieSlayer, ZombieSlayer)",
+	}
+)
+
+func banList() string {
+	listsMu.Lock()
+	defer listsMu.Unlock()
+	out := "Ban list entries:\n  Banned until - UserID (name) - Reason\n"
+	for _, b := range bans {
+		out += "  " + b + "\n"
+	}
+	return out
+}
+
+// banCommand handles "ban add <id> <duration> <unit> [reason]" and "ban remove <id>"
+func banCommand(cmd string) string {
+	f := strings.Fields(cmd)
+	listsMu.Lock()
+	defer listsMu.Unlock()
+	switch {
+	case len(f) >= 5 && f[1] == "add":
+		reason := strings.Trim(strings.Join(f[5:], " "), `"`)
+		until := time.Now().AddDate(10, 0, 0).Format("2006-01-02 15:04:05")
+		bans = append(bans, fmt.Sprintf("%s - %s (%s) - %s", until, f[2], f[2], reason))
+		return fmt.Sprintf("%s banned until %s, reason: %s\n", f[2], until, reason)
+	case len(f) == 3 && f[1] == "remove":
+		for i, b := range bans {
+			if strings.Contains(b, " - "+f[2]+" (") {
+				bans = append(bans[:i], bans[i+1:]...)
+				return fmt.Sprintf("%s removed from ban list\n", f[2])
+			}
+		}
+		return fmt.Sprintf("%s is not banned\n", f[2])
+	}
+	return "Invalid arguments\n"
+}
+
+func adminList() string {
+	listsMu.Lock()
+	defer listsMu.Unlock()
+	out := "Defined User Permissions:\n  Level: UserID (Player name if online, stored name)\n"
+	for _, a := range admins {
+		out += "      " + a + "\n"
+	}
+	out += "Defined Group Permissions:\n  Level: GroupID (Group name)\n"
+	return out
+}
+
+// adminCommand handles "admin add <id> <level> [name]" and "admin remove <id>"
+func adminCommand(cmd string) string {
+	f := strings.Fields(cmd)
+	listsMu.Lock()
+	defer listsMu.Unlock()
+	switch {
+	case len(f) >= 4 && f[1] == "add":
+		name := strings.Trim(strings.Join(f[4:], " "), `"`)
+		admins[f[2]] = fmt.Sprintf("%s: %s (, %s)", f[3], f[2], name)
+		return fmt.Sprintf("%s added with permission level of %s\n", f[2], f[3])
+	case len(f) == 3 && f[1] == "remove":
+		delete(admins, f[2])
+		return fmt.Sprintf("%s removed from the admins list\n", f[2])
 	}
+	return "Invalid arguments\n"
 }